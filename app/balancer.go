@@ -0,0 +1,274 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/node/direct"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Load balancing policies selectable with lynx.control_plane.lb_policy
+const (
+	LBRoundRobin         = "round_robin"
+	LBWeightedRoundRobin = "weighted_round_robin"
+	LBLeastConnections   = "least_connections"
+)
+
+// defaultInstanceWeight is the weight of instances without weight metadata, the same as in Kratos
+const defaultInstanceWeight = 100
+
+// ErrNoInstances is returned by a LoadBalancer asked to pick from no instances
+var ErrNoInstances = errors.New("no service instances available")
+
+// LoadBalancer picks the instance a request to a service is sent to, Pick is called concurrently
+type LoadBalancer interface {
+	Pick(instances []*registry.ServiceInstance) (*registry.ServiceInstance, error)
+}
+
+// ConnectionTracker is implemented by balancers that need to know when a request to a picked instance is done
+type ConnectionTracker interface {
+	Done(instance *registry.ServiceInstance)
+}
+
+// NewLoadBalancer returns the balancer of the named policy
+func NewLoadBalancer(policy string) (LoadBalancer, error) {
+	switch policy {
+	case LBRoundRobin:
+		return &RoundRobin{}, nil
+	case LBWeightedRoundRobin:
+		return &WeightedRoundRobin{}, nil
+	case LBLeastConnections:
+		return &LeastConnections{}, nil
+	default:
+		return nil, fmt.Errorf("unknown load balancing policy %q", policy)
+	}
+}
+
+// RoundRobin picks the instances one after another
+type RoundRobin struct {
+	next uint64
+}
+
+func (r *RoundRobin) Pick(instances []*registry.ServiceInstance) (*registry.ServiceInstance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoInstances
+	}
+	i := atomic.AddUint64(&r.next, 1) - 1
+	return instances[i%uint64(len(instances))], nil
+}
+
+// WeightedRoundRobin picks the instances in proportion to their weight metadata using smooth weighted round
+// robin, so a heavy instance is interleaved with the others instead of being picked several times in a row
+type WeightedRoundRobin struct {
+	mu      sync.Mutex
+	current map[string]int64
+}
+
+func (w *WeightedRoundRobin) Pick(instances []*registry.ServiceInstance) (*registry.ServiceInstance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoInstances
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Forget instances that left the service once the state grows well beyond the current instances
+	if w.current == nil || len(w.current) > 2*len(instances) {
+		w.current = make(map[string]int64, len(instances))
+	}
+	var (
+		total    int64
+		selected *registry.ServiceInstance
+		best     int64
+	)
+	for _, ins := range instances {
+		k := instanceKey(ins)
+		weight := instanceWeight(ins)
+		total += weight
+		w.current[k] += weight
+		if selected == nil || w.current[k] > best {
+			selected, best = ins, w.current[k]
+		}
+	}
+	w.current[instanceKey(selected)] -= total
+	return selected, nil
+}
+
+// LeastConnections picks the instance with the fewest requests in flight, ties are broken round robin. Callers
+// report finished requests with Done.
+type LeastConnections struct {
+	mu     sync.Mutex
+	next   int
+	active map[string]int64
+}
+
+func (l *LeastConnections) Pick(instances []*registry.ServiceInstance) (*registry.ServiceInstance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoInstances
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active == nil {
+		l.active = make(map[string]int64)
+	}
+	l.next++
+	var selected *registry.ServiceInstance
+	for i := range instances {
+		ins := instances[(l.next+i)%len(instances)]
+		if selected == nil || l.active[instanceKey(ins)] < l.active[instanceKey(selected)] {
+			selected = ins
+		}
+	}
+	l.active[instanceKey(selected)]++
+	return selected, nil
+}
+
+// Done releases a request picked with Pick
+func (l *LeastConnections) Done(instance *registry.ServiceInstance) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	k := instanceKey(instance)
+	if l.active[k] <= 1 {
+		delete(l.active, k)
+		return
+	}
+	l.active[k]--
+}
+
+// instanceKey identifies an instance by its id, or by its endpoints when it has none
+func instanceKey(ins *registry.ServiceInstance) string {
+	if ins.ID != "" {
+		return ins.ID
+	}
+	return strings.Join(ins.Endpoints, ",")
+}
+
+// instanceWeight reads the weight metadata of an instance
+func instanceWeight(ins *registry.ServiceInstance) int64 {
+	if w, err := strconv.ParseInt(ins.Metadata["weight"], 10, 64); err == nil && w > 0 {
+		return w
+	}
+	return defaultInstanceWeight
+}
+
+// PickInstance resolves a service through the control plane discovery and picks one of its instances with the
+// configured load balancing policy
+func PickInstance(ctx context.Context, service string) (*registry.ServiceInstance, error) {
+	discovery := ServiceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("the control plane provides no service discovery")
+	}
+	instances, err := discovery.GetService(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	lb := Lynx().loadBalancer(service)
+	ins, err := lb.Pick(instances)
+	if err != nil {
+		return nil, err
+	}
+	// Resolving doesn't send a request, the instance is picked but not kept busy
+	if t, ok := lb.(ConnectionTracker); ok {
+		t.Done(ins)
+	}
+	return ins, nil
+}
+
+// lbPolicy returns the configured load balancing policy, weighted round robin when none or an unknown one is
+// configured
+func (a *LynxApp) lbPolicy() string {
+	policy := a.bootConf.GetLynx().GetControlPlane().GetLbPolicy()
+	if policy == "" {
+		return LBWeightedRoundRobin
+	}
+	if _, err := NewLoadBalancer(policy); err != nil {
+		a.Helper().Errorf("Falling back to weighted round robin: %v", err)
+		return LBWeightedRoundRobin
+	}
+	return policy
+}
+
+// loadBalancer returns the balancer of a service. Every service has its own, the state of weighted round robin
+// and least connections is only meaningful for one set of instances.
+func (a *LynxApp) loadBalancer(service string) LoadBalancer {
+	a.lbMu.Lock()
+	defer a.lbMu.Unlock()
+	if lb, ok := a.lbs[service]; ok {
+		return lb
+	}
+	if a.lbs == nil {
+		a.lbs = make(map[string]LoadBalancer)
+	}
+	lb, _ := NewLoadBalancer(a.lbPolicy())
+	a.lbs[service] = lb
+	return lb
+}
+
+// applyLoadBalancer makes the gRPC subscriptions pick instances with the configured policy, without a configured
+// policy the Kratos default stays in place
+func (a *LynxApp) applyLoadBalancer() {
+	if a == nil || a.bootConf.GetLynx().GetControlPlane().GetLbPolicy() == "" {
+		return
+	}
+	policy := a.lbPolicy()
+	selector.SetGlobalSelector(&selector.DefaultBuilder{
+		Node:     &direct.Builder{},
+		Balancer: &balancerBuilder{policy: policy},
+	})
+	a.Helper().Infof("Load balancing policy %v applied", policy)
+}
+
+// balancerBuilder builds a selectorBalancer of the policy for every subscription
+type balancerBuilder struct {
+	policy string
+}
+
+func (b *balancerBuilder) Build() selector.Balancer {
+	lb, err := NewLoadBalancer(b.policy)
+	if err != nil {
+		lb = &WeightedRoundRobin{}
+	}
+	return &selectorBalancer{lb: lb}
+}
+
+// selectorBalancer adapts a LoadBalancer to the Kratos selector of a single subscription
+type selectorBalancer struct {
+	lb LoadBalancer
+}
+
+func (b *selectorBalancer) Pick(_ context.Context, nodes []selector.WeightedNode) (selector.WeightedNode, selector.DoneFunc, error) {
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	instances := make([]*registry.ServiceInstance, len(nodes))
+	for i, n := range nodes {
+		instances[i] = &registry.ServiceInstance{
+			ID:        n.Scheme() + "://" + n.Address(),
+			Name:      n.ServiceName(),
+			Version:   n.Version(),
+			Metadata:  n.Metadata(),
+			Endpoints: []string{n.Scheme() + "://" + n.Address()},
+		}
+	}
+	ins, err := b.lb.Pick(instances)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range instances {
+		if instances[i] != ins {
+			continue
+		}
+		done := nodes[i].Pick()
+		return nodes[i], func(ctx context.Context, di selector.DoneInfo) {
+			done(ctx, di)
+			if t, ok := b.lb.(ConnectionTracker); ok {
+				t.Done(ins)
+			}
+		}, nil
+	}
+	return nil, nil, fmt.Errorf("load balancer picked an unknown instance %v", instanceKey(ins))
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/node/direct"
+	"testing"
+)
+
+func balancerInstances(weights ...int) []*registry.ServiceInstance {
+	instances := make([]*registry.ServiceInstance, len(weights))
+	for i, w := range weights {
+		instances[i] = &registry.ServiceInstance{
+			ID:       fmt.Sprintf("instance-%v", i),
+			Metadata: map[string]string{"weight": fmt.Sprint(w)},
+		}
+	}
+	return instances
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	instances := balancerInstances(5, 1, 1)
+	lb := &WeightedRoundRobin{}
+	picks := make(map[string]int)
+	var order []string
+	for i := 0; i < 7; i++ {
+		ins, err := lb.Pick(instances)
+		if err != nil {
+			t.Fatal(err)
+		}
+		picks[ins.ID]++
+		order = append(order, ins.ID)
+	}
+	if picks["instance-0"] != 5 || picks["instance-1"] != 1 || picks["instance-2"] != 1 {
+		t.Fatalf("picks not proportional to weights: %v", picks)
+	}
+	// Smooth weighted round robin interleaves the light instances instead of picking the heavy one five times
+	for i := 2; i < len(order); i++ {
+		if order[i] == "instance-0" && order[i-1] == "instance-0" && order[i-2] == "instance-0" {
+			t.Fatalf("heavy instance picked three times in a row: %v", order)
+		}
+	}
+}
+
+func TestLeastConnections(t *testing.T) {
+	instances := balancerInstances(1, 1)
+	lb := &LeastConnections{}
+	first, _ := lb.Pick(instances)
+	second, _ := lb.Pick(instances)
+	if first == second {
+		t.Fatalf("picked busy instance %v again", first.ID)
+	}
+	lb.Done(first)
+	if third, _ := lb.Pick(instances); third != first {
+		t.Fatalf("expected released instance %v, got %v", first.ID, third.ID)
+	}
+}
+
+func BenchmarkLoadBalancers(b *testing.B) {
+	instances := balancerInstances(10, 20, 30, 40, 50, 60, 70, 80)
+	for _, policy := range []string{LBRoundRobin, LBWeightedRoundRobin, LBLeastConnections} {
+		b.Run(policy, func(b *testing.B) {
+			lb, err := NewLoadBalancer(policy)
+			if err != nil {
+				b.Fatal(err)
+			}
+			tracker, _ := lb.(ConnectionTracker)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					ins, err := lb.Pick(instances)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if tracker != nil {
+						tracker.Done(ins)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestBalancerPerSubscription(t *testing.T) {
+	builder := &balancerBuilder{policy: LBWeightedRoundRobin}
+	nodes := func(weights ...int) []selector.WeightedNode {
+		var nodes []selector.WeightedNode
+		for i, ins := range balancerInstances(weights...) {
+			ins.Endpoints = []string{fmt.Sprintf("grpc://%v:9000", ins.ID)}
+			nodes = append(nodes, (&direct.Builder{}).Build(selector.NewNode("grpc", fmt.Sprintf("10.0.0.%v:9000", i), ins)))
+		}
+		return nodes
+	}
+	users, orders := nodes(5, 1, 1), nodes(1, 1, 1, 1, 1, 1, 1, 1)
+	usersBalancer, ordersBalancer := builder.Build(), builder.Build()
+
+	picks := make(map[string]int)
+	for i := 0; i < 70; i++ {
+		n, done, err := usersBalancer.Pick(context.Background(), users)
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+		picks[n.Address()]++
+		// Picks of another subscription in between must not disturb the weights
+		if _, done, err = ordersBalancer.Pick(context.Background(), orders); err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+	}
+	if picks["10.0.0.0:9000"] != 50 || picks["10.0.0.1:9000"] != 10 || picks["10.0.0.2:9000"] != 10 {
+		t.Fatalf("picks not proportional to weights: %v", picks)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// defaultStartupBarrierTimeout is used when lynx.startup_barrier.timeout is not configured
+const defaultStartupBarrierTimeout = time.Minute
+
+// WaitForPeers blocks until lynx.startup_barrier.min_peers instances of the service are registered in service
+// discovery, so a single replica doesn't take all traffic during a rolling restart. When the peers don't show up
+// in time, or no discovery is available, startup proceeds with a warning.
+func WaitForPeers(ctx context.Context) {
+	barrier := Lynx().bootConf.GetLynx().GetStartupBarrier()
+	minPeers := int(barrier.GetMinPeers())
+	if minPeers <= 0 {
+		return
+	}
+	service := barrier.GetService()
+	if service == "" {
+		service = Name()
+	}
+	timeout := defaultStartupBarrierTimeout
+	if t := barrier.GetTimeout(); t != nil {
+		timeout = t.AsDuration()
+	}
+
+	discovery := ServiceDiscovery()
+	if discovery == nil {
+		Lynx().Helper().Warnf("Startup barrier skipped, the control plane provides no service discovery")
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	watcher, err := discovery.Watch(ctx, service)
+	if err != nil {
+		Lynx().Helper().Warnf("Startup barrier skipped, failed to watch %v: %v", service, err)
+		return
+	}
+	defer watcher.Stop()
+
+	Lynx().Helper().Infof("Waiting for %v instances of %v before completing startup", minPeers, service)
+	peers := 0
+	for {
+		instances, err := watcher.Next()
+		if err != nil {
+			Lynx().Helper().Warnf("Startup barrier gave up with %v of %v instances of %v, starting anyway: %v",
+				peers, minPeers, service, err)
+			return
+		}
+		peers = len(instances)
+		if peers >= minPeers {
+			Lynx().Helper().Infof("Startup barrier passed, %v instances of %v registered", peers, service)
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/registry"
+	"testing"
+	"time"
+)
+
+// peersControlPlane serves a discovery that reports a fixed number of instances of every service
+type peersControlPlane struct {
+	LocalControlPlane
+	peers int
+}
+
+func (p *peersControlPlane) NewServiceDiscovery() registry.Discovery {
+	return &peersDiscovery{peers: p.peers}
+}
+
+type peersDiscovery struct {
+	peers int
+}
+
+func (d *peersDiscovery) GetService(context.Context, string) ([]*registry.ServiceInstance, error) {
+	return balancerInstances(make([]int, d.peers)...), nil
+}
+
+func (d *peersDiscovery) Watch(ctx context.Context, _ string) (registry.Watcher, error) {
+	return &peersWatcher{ctx: ctx, peers: d.peers}, nil
+}
+
+// peersWatcher reports the instances once and then blocks until its context is done, like a discovery without
+// further changes
+type peersWatcher struct {
+	ctx   context.Context
+	peers int
+	sent  bool
+}
+
+func (w *peersWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.sent {
+		w.sent = true
+		return balancerInstances(make([]int, w.peers)...), nil
+	}
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *peersWatcher) Stop() error {
+	return nil
+}
+
+func TestWaitForPeers(t *testing.T) {
+	tests := []struct {
+		name  string
+		peers int
+		wait  bool
+	}{
+		{"enough peers", 2, false},
+		{"too few peers", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestApp(t, `"startup_barrier": {"min_peers": 2, "timeout": "0.05s"}`)
+			Lynx().SetControlPlane(&peersControlPlane{peers: tt.peers})
+
+			st := time.Now()
+			WaitForPeers(context.Background())
+			waited := time.Since(st)
+			if tt.wait && waited < 50*time.Millisecond {
+				t.Errorf("Expected to wait for the timeout, returned after %v", waited)
+			}
+			if waited > time.Second {
+				t.Errorf("Expected to return by the timeout, returned after %v", waited)
+			}
+			if !tt.wait && waited >= 50*time.Millisecond {
+				t.Errorf("Expected to return once the peers registered, returned after %v", waited)
+			}
+		})
+	}
+}
+
+func TestWaitForPeersWithoutDiscovery(t *testing.T) {
+	newTestApp(t, `"startup_barrier": {"min_peers": 2, "timeout": "10s"}`)
+
+	done := make(chan struct{})
+	go func() {
+		WaitForPeers(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the barrier to be skipped without service discovery")
+	}
+}
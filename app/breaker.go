@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/config"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx/conf"
+	"github.com/go-lynx/lynx/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Circuit breaker states reported by BreakerChange
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half_open"
+	BreakerOpen     = "open"
+)
+
+// Defaults for lynx.control_plane.breaker settings that are not configured
+const (
+	defaultBreakerMinRequests = 20
+	defaultBreakerWindow      = 10 * time.Second
+	defaultBreakerOpenTimeout = 5 * time.Second
+)
+
+// breakerKey is the configuration key of the circuit breaker settings
+const breakerKey = "lynx.control_plane.breaker"
+
+// ErrCircuitOpen is returned for calls to a target whose circuit is open
+var ErrCircuitOpen = kerrors.ServiceUnavailable("CIRCUIT_OPEN", "circuit breaker is open")
+
+var (
+	breakerState = promauto.With(metrics.Registry()).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lynx_circuit_breaker_state",
+		Help: "Circuit breaker state per target: 0 closed, 1 half open, 2 open.",
+	}, []string{"target"})
+	breakerStateValues = map[string]float64{BreakerClosed: 0, BreakerHalfOpen: 1, BreakerOpen: 2}
+
+	breakersMu   sync.Mutex
+	breakers     = make(map[string]*CircuitBreaker)
+	breakerHooks []func(BreakerChange)
+	// breakerConf holds the current *conf.Breaker, replaced on config reload
+	breakerConf atomic.Value
+)
+
+// BreakerChange reports a circuit breaker changing its state
+type BreakerChange struct {
+	Target        string `json:"target"`
+	State         string `json:"state"`
+	PreviousState string `json:"previous_state"`
+}
+
+// OnBreakerChange registers a hook called when the circuit of a target opens, half opens or closes
+func OnBreakerChange(fn func(BreakerChange)) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breakerHooks = append(breakerHooks, fn)
+}
+
+// CircuitBreaker fails calls to a target fast once the failure ratio of a window exceeds the configured
+// threshold. After the open timeout a single probe call is let through, its outcome closes or reopens the circuit.
+type CircuitBreaker struct {
+	target string
+
+	mu          sync.Mutex
+	state       string
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+// Breaker returns the circuit breaker of a target, typically the name of a subscribed service
+func Breaker(target string) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[target]
+	if !ok {
+		b = &CircuitBreaker{target: target, state: BreakerClosed}
+		breakers[target] = b
+		breakerState.WithLabelValues(target).Set(breakerStateValues[BreakerClosed])
+	}
+	return b
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a call may be dispatched, it returns ErrCircuitOpen while the circuit is open. Every
+// allowed call must be reported with Record.
+func (b *CircuitBreaker) Allow() error {
+	c := currentBreakerConf()
+	if c.GetFailureRatio() <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	previous := b.state
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < breakerOpenTimeout(c) {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	state := b.state
+	b.mu.Unlock()
+	b.changed(previous, state)
+	return nil
+}
+
+// Record reports the outcome of a call allowed by Allow
+func (b *CircuitBreaker) Record(err error) {
+	c := currentBreakerConf()
+	failed := isBreakerFailure(err)
+	b.mu.Lock()
+	previous := b.state
+	switch {
+	case c.GetFailureRatio() <= 0:
+		// Disabling the breaker by a reload closes a circuit left open or half open
+		b.state = BreakerClosed
+		b.probing = false
+	case b.state == BreakerHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.state = BreakerClosed
+			b.resetWindow()
+		}
+	case b.state == BreakerClosed:
+		if time.Since(b.windowStart) >= breakerWindow(c) {
+			b.resetWindow()
+		}
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= breakerMinRequests(c) && float64(b.failures)/float64(b.requests) >= c.GetFailureRatio() {
+			b.open()
+		}
+	}
+	state := b.state
+	b.mu.Unlock()
+	b.changed(previous, state)
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+}
+
+func (b *CircuitBreaker) resetWindow() {
+	b.windowStart = time.Now()
+	b.requests = 0
+	b.failures = 0
+}
+
+// changed updates the state gauge and calls the hooks when the state changed
+func (b *CircuitBreaker) changed(previous, state string) {
+	if previous == state {
+		return
+	}
+	breakerState.WithLabelValues(b.target).Set(breakerStateValues[state])
+	if state == BreakerOpen {
+		Lynx().Helper().Warnf("Circuit breaker of %v opened", b.target)
+	} else if state == BreakerClosed {
+		Lynx().Helper().Infof("Circuit breaker of %v closed", b.target)
+	}
+	breakersMu.Lock()
+	hooks := append([]func(BreakerChange){}, breakerHooks...)
+	breakersMu.Unlock()
+	for _, fn := range hooks {
+		fn(BreakerChange{Target: b.target, State: state, PreviousState: previous})
+	}
+}
+
+// CircuitBreakerClient is a client middleware consulting the circuit breaker of target before every call
+func CircuitBreakerClient(target string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			b := Breaker(target)
+			if err := b.Allow(); err != nil {
+				return nil, err
+			}
+			reply, err := handler(ctx, req)
+			b.Record(err)
+			return reply, err
+		}
+	}
+}
+
+// isBreakerFailure counts server errors and timeouts against a target, client errors such as not found or
+// invalid arguments don't say anything about its health
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return kerrors.FromError(err).GetCode() >= 500
+}
+
+// currentBreakerConf returns the breaker settings of the last reload, or those of the bootstrap configuration
+func currentBreakerConf() *conf.Breaker {
+	if c, ok := breakerConf.Load().(*conf.Breaker); ok {
+		return c
+	}
+	if Lynx() == nil {
+		return nil
+	}
+	return Lynx().bootConf.GetLynx().GetControlPlane().GetBreaker()
+}
+
+// reconfigureBreakers applies the breaker settings of a reloaded configuration, open circuits keep their state
+func reconfigureBreakers(next config.Config) {
+	var b conf.Bootstrap
+	if err := next.Scan(&b); err != nil {
+		Lynx().Helper().Errorf("Keeping the previous circuit breaker settings: %v", err)
+		return
+	}
+	c := b.GetLynx().GetControlPlane().GetBreaker()
+	if c == nil {
+		c = &conf.Breaker{}
+	}
+	breakerConf.Store(c)
+}
+
+func breakerMinRequests(c *conf.Breaker) int {
+	if n := int(c.GetMinRequests()); n > 0 {
+		return n
+	}
+	return defaultBreakerMinRequests
+}
+
+func breakerWindow(c *conf.Breaker) time.Duration {
+	if d := c.GetWindow().AsDuration(); d > 0 {
+		return d
+	}
+	return defaultBreakerWindow
+}
+
+func breakerOpenTimeout(c *conf.Breaker) time.Duration {
+	if d := c.GetOpenTimeout().AsDuration(); d > 0 {
+		return d
+	}
+	return defaultBreakerOpenTimeout
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"errors"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx/conf"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	newTestApp(t, "")
+	breakerConf.Store(&conf.Breaker{FailureRatio: 0.5, MinRequests: 4, OpenTimeout: durationpb.New(20 * time.Millisecond)})
+	t.Cleanup(func() {
+		breakerConf.Store(&conf.Breaker{})
+	})
+	var changes []string
+	OnBreakerChange(func(c BreakerChange) {
+		if c.Target == "orders" {
+			changes = append(changes, c.State)
+		}
+	})
+
+	b := Breaker("orders")
+	call := func(err error) error {
+		if allowErr := b.Allow(); allowErr != nil {
+			return allowErr
+		}
+		b.Record(err)
+		return nil
+	}
+	// Client errors don't count against the target, two server errors in four calls open the circuit
+	_ = call(nil)
+	_ = call(kerrors.NotFound("NOT_FOUND", "order not found"))
+	_ = call(kerrors.InternalServer("INTERNAL", "boom"))
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected the circuit to stay closed below min_requests, but it is %v", b.State())
+	}
+	_ = call(kerrors.InternalServer("INTERNAL", "boom"))
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected the circuit to open, but it is %v", b.State())
+	}
+	if err := call(nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, but got %v", err)
+	}
+
+	// After the open timeout a single probe is let through, a failed probe reopens the circuit
+	time.Sleep(30 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected the probe to be allowed, but got %v", err)
+	}
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("Expected the circuit to half open, but it is %v", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a second call during the probe to fail fast, but got %v", err)
+	}
+	b.Record(kerrors.ServiceUnavailable("UNAVAILABLE", "down"))
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected a failed probe to reopen the circuit, but it is %v", b.State())
+	}
+
+	// A successful probe closes it
+	time.Sleep(30 * time.Millisecond)
+	if err := call(nil); err != nil {
+		t.Fatalf("Expected the probe to be allowed, but got %v", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected a successful probe to close the circuit, but it is %v", b.State())
+	}
+
+	want := []string{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected changes %v, but got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("Expected changes %v, but got %v", want, changes)
+		}
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	newTestApp(t, "")
+	breakerConf.Store(&conf.Breaker{})
+	b := Breaker("payments")
+	for i := 0; i < 50; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Expected calls to pass without a failure ratio, but got %v", err)
+		}
+		b.Record(kerrors.InternalServer("INTERNAL", "boom"))
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("Expected the circuit to stay closed, but it is %v", b.State())
+	}
+}
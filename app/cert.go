@@ -1,11 +1,39 @@
 package app
 
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+)
+
 type Cert interface {
 	GetCrt() []byte
 	GetKey() []byte
 	GetRootCA() []byte
 }
 
+// CertReloader is implemented by certificates that can be rotated without a restart
+type CertReloader interface {
+	Reload() error
+}
+
+// TLSCertificate is implemented by certificates that keep their parsed key pair, so a rotated certificate is
+// parsed once rather than on every handshake
+type TLSCertificate interface {
+	TLSCertificate() *tls.Certificate
+}
+
+// ClientVerifier is implemented by certificates that configure mutual TLS, a nil pool keeps the root CA and a nil
+// callback leaves the verification to the pool alone. RequiresClientCert reports whether the verification only
+// works on client certificates verified against the pool.
+type ClientVerifier interface {
+	ClientCAs() *x509.CertPool
+	VerifyPeerCertificate() func(rawCerts [][]byte, chains [][]*x509.Certificate) error
+	RequiresClientCert() bool
+}
+
 func (a *LynxApp) Cert() Cert {
 	return a.cert
 }
@@ -13,3 +41,117 @@ func (a *LynxApp) Cert() Cert {
 func (a *LynxApp) SetCert(cert Cert) {
 	a.cert = cert
 }
+
+// GetCertificate returns the current application certificate, use it as tls.Config.GetCertificate so new
+// handshakes see a rotated certificate while established connections keep the one they negotiated
+func GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c := Lynx().Cert()
+	if t, ok := c.(TLSCertificate); ok {
+		return t.TLSCertificate(), nil
+	}
+	tlsCert, err := tls.X509KeyPair(c.GetCrt(), c.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	return &tlsCert, nil
+}
+
+// rootCAs caches the pool of the root CA of the application certificate, a rotated root CA is parsed once on the
+// next handshake
+var rootCAs struct {
+	mu   sync.Mutex
+	pem  []byte
+	pool *x509.CertPool
+}
+
+// RootCAs returns a pool holding the current root CA of the application certificate
+func RootCAs() (*x509.CertPool, error) {
+	pem := Lynx().Cert().GetRootCA()
+	rootCAs.mu.Lock()
+	defer rootCAs.mu.Unlock()
+	if rootCAs.pool != nil && bytes.Equal(rootCAs.pem, pem) {
+		return rootCAs.pool, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("failed to load the root CA certificate")
+	}
+	rootCAs.pem, rootCAs.pool = pem, pool
+	return pool, nil
+}
+
+// ServerTLSConfig returns the TLS configuration of a server. The certificate and the client CA pool are taken from
+// the application certificate on every handshake through GetConfigForClient, so a rotated certificate or root CA
+// applies to new connections without a restart. The configuration of a handshake replaces the one the server
+// derives its ALPN protocols in, nextProtos lists them, e.g. "h2" for gRPC.
+func ServerTLSConfig(clientAuth tls.ClientAuthType, nextProtos ...string) (*tls.Config, error) {
+	if _, err := GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	if _, err := RootCAs(); err != nil {
+		return nil, err
+	}
+	base := MutualTLS(&tls.Config{
+		GetCertificate: GetCertificate,
+		ServerName:     Name(),
+		ClientAuth:     clientAuth,
+		NextProtos:     nextProtos,
+	})
+	c := base.Clone()
+	c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		handshake := base.Clone()
+		// A pool set with the mutual TLS settings takes precedence over the root CA
+		if handshake.ClientCAs == nil {
+			pool, err := RootCAs()
+			if err != nil {
+				return nil, err
+			}
+			handshake.ClientCAs = pool
+		}
+		return handshake, nil
+	}
+	return c, nil
+}
+
+// VerifyServerCertificate verifies the certificate of a server against the current root CA of the application
+// certificate, for clients that follow a rotated root CA. Use it as tls.Config.VerifyConnection together with
+// InsecureSkipVerify, which only skips the verification against a pool fixed when the configuration is created.
+func VerifyServerCertificate(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no server certificate presented")
+	}
+	pool, err := RootCAs()
+	if err != nil {
+		return err
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err = cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// MutualTLS applies the mutual TLS settings of the application certificate to a server TLS configuration. When the
+// certificate requires verified client certificates, any other client auth type is replaced with
+// tls.RequireAndVerifyClientCert, otherwise unverified or missing client certificates would bypass the checks.
+func MutualTLS(c *tls.Config) *tls.Config {
+	v, ok := Lynx().Cert().(ClientVerifier)
+	if !ok {
+		return c
+	}
+	if pool := v.ClientCAs(); pool != nil {
+		c.ClientCAs = pool
+	}
+	c.VerifyPeerCertificate = v.VerifyPeerCertificate()
+	if v.RequiresClientCert() && c.ClientAuth != tls.RequireAndVerifyClientCert {
+		Lynx().Helper().Warnf("Client identities are only checked on verified certificates, using %v instead of %v",
+			tls.RequireAndVerifyClientCert, c.ClientAuth)
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return c
+}
@@ -6,6 +6,12 @@ import (
 
 // PreparePlug Bootstrap plugin loading through remote or local configuration files
 func (m *DefaultLynxPluginManager) PreparePlug(config config.Config) []string {
+	// 在创建插件之前检查配置的嵌套深度和值大小
+	if err := CheckConfig(config); err != nil {
+		Lynx().Helper().Errorf("Invalid configuration: %v", err)
+		panic(err)
+	}
+
 	// 获取所有已注册插件的配置前缀列表
 	table := m.factory.GetRegisterTable()
 	// 初始化一个字符串切片，用于存储将要加载的插件名称
@@ -47,6 +53,11 @@ func (m *DefaultLynxPluginManager) PreparePlug(config config.Config) []string {
 			}
 		}
 	}
+	// 同名插件只会保留一个，直接报错而不是静默丢弃
+	if err := checkDuplicates(m.pluginList); err != nil {
+		Lynx().Helper().Errorf("Exception in preparing pluginList : %v", err)
+		panic(err)
+	}
 	// 返回将要加载的插件名称列表
 	return plugNames
 }
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"sort"
+	"strings"
+)
+
+// Defaults used when lynx.config_limits is not configured
+const (
+	defaultMaxConfigDepth     = 32
+	defaultMaxConfigValueSize = 1 << 20
+)
+
+// maxReportedConfigViolations bounds the paths listed in a ConfigLimitError
+const maxReportedConfigViolations = 10
+
+// ConfigLimitError lists the configuration paths exceeding the configured depth or value size
+type ConfigLimitError struct {
+	Violations []string
+}
+
+func (e *ConfigLimitError) Error() string {
+	return "configuration exceeds limits: " + strings.Join(e.Violations, "; ")
+}
+
+// CheckConfig walks the configuration tree and rejects it when it is nested deeper or holds larger values than
+// lynx.config_limits allow, so pathological configurations fail up front instead of deep in a plugin
+func CheckConfig(c config.Config) error {
+	maxDepth, maxSize := defaultMaxConfigDepth, defaultMaxConfigValueSize
+	if Lynx() != nil && Lynx().bootConf != nil {
+		limits := Lynx().bootConf.GetLynx().GetConfigLimits()
+		if limits.GetMaxDepth() > 0 {
+			maxDepth = int(limits.GetMaxDepth())
+		}
+		if limits.GetMaxValueSize() > 0 {
+			maxSize = int(limits.GetMaxValueSize())
+		}
+	}
+
+	var root map[string]interface{}
+	if err := c.Scan(&root); err != nil {
+		return err
+	}
+	var violations []string
+	var walk func(path string, v interface{}, depth int)
+	walk = func(path string, v interface{}, depth int) {
+		if depth > maxDepth {
+			violations = append(violations, fmt.Sprintf("%v is nested deeper than %v levels", path, maxDepth))
+			return
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				if path == "" {
+					walk(k, child, depth+1)
+				} else {
+					walk(path+"."+k, child, depth+1)
+				}
+			}
+		case []interface{}:
+			for i, child := range t {
+				walk(fmt.Sprintf("%v[%v]", path, i), child, depth+1)
+			}
+		case string:
+			if len(t) > maxSize {
+				violations = append(violations, fmt.Sprintf("%v holds %v bytes, more than %v", path, len(t), maxSize))
+			}
+		}
+	}
+	walk("", root, 0)
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+	if len(violations) > maxReportedConfigViolations {
+		violations = append(violations[:maxReportedConfigViolations],
+			fmt.Sprintf("and %v more", len(violations)-maxReportedConfigViolations))
+	}
+	return &ConfigLimitError{Violations: violations}
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// defaultReloadDebounce is used when lynx.plugins.reload_debounce is not configured
+const defaultReloadDebounce = time.Second
+
+// ConfigChange reports a plugin reconfigured after its configuration changed in the watched sources
+type ConfigChange struct {
+	Plugin string `json:"plugin"`
+	Error  string `json:"error,omitempty"`
+	// ReloadFailed is set when the plugin rejected a configuration applied with ReloadConfig and the plugins were
+	// rolled back to the previous configuration
+	ReloadFailed bool `json:"reload_failed,omitempty"`
+}
+
+// OnConfigChange registers a hook called after a plugin was reconfigured from a changed configuration, the
+// change carries the error when the plugin rejected the configuration. It is called as well when a plugin rejects
+// a configuration reload.
+func (m *DefaultLynxPluginManager) OnConfigChange(fn func(ConfigChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configHooks = append(m.configHooks, fn)
+}
+
+// watchConfig watches the configuration of every loaded Configurable plugin in the global configuration. Changes
+// are debounced per plugin and applied with Configure, a plugin keeps running on its previous configuration when
+// it rejects the new one. The circuit breaker settings are watched as well.
+func (m *DefaultLynxPluginManager) watchConfig() {
+	if Lynx() == nil || Lynx().GlobalConfig() == nil {
+		return
+	}
+	c := Lynx().GlobalConfig()
+	m.mu.Lock()
+	if m.watchedConf == c {
+		m.mu.Unlock()
+		return
+	}
+	m.watchedConf = c
+	m.mu.Unlock()
+
+	m.watchBreakers(c)
+	for _, p := range m.loadedPlugins() {
+		m.watchPluginConfig(c, p)
+	}
+}
+
+// watchPlugin watches the configuration of a plugin loaded after watchConfig, e.g. one that was restarted
+func (m *DefaultLynxPluginManager) watchPlugin(p plugin.Plugin) {
+	m.mu.Lock()
+	c := m.watchedConf
+	m.mu.Unlock()
+	if c != nil {
+		m.watchPluginConfig(c, p)
+	}
+}
+
+// watchPluginConfig watches the configuration of p in c when p is Configurable, watching it again replaces the
+// previous watch
+func (m *DefaultLynxPluginManager) watchPluginConfig(c config.Config, p plugin.Plugin) {
+	if _, ok := p.(plugin.Configurable); !ok {
+		return
+	}
+	err := c.Watch(p.ConfPrefix(), func(string, config.Value) {
+		m.configChanged(c, p)
+	})
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		Lynx().PluginHelper(p.Name()).Warnf("Configuration of %v plugin is not watched: %v", p.Name(), err)
+	}
+}
+
+// watchBreakers applies changed circuit breaker settings. Only existing keys can be watched, without breaker
+// settings in the configuration the whole lynx section is watched for them to appear.
+func (m *DefaultLynxPluginManager) watchBreakers(c config.Config) {
+	observer := func(string, config.Value) {
+		m.mu.Lock()
+		watched := m.watchedConf == c
+		m.mu.Unlock()
+		if watched {
+			reconfigureBreakers(c)
+		}
+	}
+	err := c.Watch(breakerKey, observer)
+	if errors.Is(err, config.ErrNotFound) {
+		err = c.Watch("lynx", observer)
+	}
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		Lynx().Helper().Warnf("Circuit breaker settings are not watched: %v", err)
+	}
+}
+
+// stopConfigWatch ignores further configuration changes, the watches end with the configuration
+func (m *DefaultLynxPluginManager) stopConfigWatch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchedConf = nil
+	for name, t := range m.configTimers {
+		t.Stop()
+		delete(m.configTimers, name)
+	}
+}
+
+// configChanged (re)starts the debounce timer of p
+func (m *DefaultLynxPluginManager) configChanged(c config.Config, p plugin.Plugin) {
+	debounce := Lynx().pluginsConf().GetReloadDebounce().AsDuration()
+	if debounce <= 0 {
+		debounce = defaultReloadDebounce
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.watchedConf != c {
+		return
+	}
+	if m.configTimers == nil {
+		m.configTimers = make(map[string]*time.Timer)
+	}
+	if t := m.configTimers[p.Name()]; t != nil {
+		t.Stop()
+	}
+	m.configTimers[p.Name()] = time.AfterFunc(debounce, func() {
+		m.reconfigure(c, p)
+	})
+}
+
+// reconfigure applies the current configuration of p, serialized with ReloadConfig
+func (m *DefaultLynxPluginManager) reconfigure(c config.Config, p plugin.Plugin) {
+	m.mu.Lock()
+	watched := m.watchedConf == c
+	m.mu.Unlock()
+	// A plugin that is being restarted picks up the configuration when it loads
+	if _, err := m.loadedPlugin(p.Name()); !watched || err != nil {
+		return
+	}
+
+	change := ConfigChange{Plugin: p.Name()}
+	if err := m.configure(c, p); err != nil {
+		change.Error = err.Error()
+		Lynx().PluginHelper(p.Name()).Errorf("Reconfiguring %v plugin failed, keeping its previous configuration: %v", p.Name(), err)
+	} else {
+		Lynx().PluginHelper(p.Name()).Infof("Plugin %v reconfigured after a configuration change", p.Name())
+	}
+
+	m.notifyConfigChange(change)
+}
+
+// notifyConfigChange calls the OnConfigChange hooks
+func (m *DefaultLynxPluginManager) notifyConfigChange(change ConfigChange) {
+	m.mu.Lock()
+	hooks := append([]func(ConfigChange){}, m.configHooks...)
+	m.mu.Unlock()
+	for _, fn := range hooks {
+		fn(change)
+	}
+}
+
+// configure calls Configure of p, turning a panic into an error so a faulty plugin can't crash the application
+func (m *DefaultLynxPluginManager) configure(c config.Config, p plugin.Plugin) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while configuring: %v", r)
+		}
+	}()
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+	return configureWith(p.(plugin.Configurable), p, c)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"github.com/go-lynx/lynx/plugin"
+	"sync"
+	"time"
+)
+
+// defaultDrainTimeout is used when lynx.shutdown.drain_timeout is not configured
+const defaultDrainTimeout = 10 * time.Second
+
+// phaseDrain is the ShutdownSummary phase holding the time spent draining
+const phaseDrain = "drain"
+
+// Drain lets all Drainable plugins finish their active requests in parallel, so requests in flight during a
+// rolling deploy aren't cut off by the servers or the stores they use going away. It must run after the instance
+// is deregistered and before the Kratos application stops its servers, boot drains from the registrar of the
+// application. Only the first call drains, UnloadPluginsContext calls it as well for applications stopped without
+// the boot options.
+func (m *DefaultLynxPluginManager) Drain(ctx context.Context) {
+	m.mu.Lock()
+	if m.drained {
+		m.mu.Unlock()
+		return
+	}
+	m.drained = true
+	m.mu.Unlock()
+
+	start := time.Now()
+	m.drain(ctx)
+	m.mu.Lock()
+	m.drainDuration = time.Since(start)
+	m.mu.Unlock()
+}
+
+// drain runs Drain of all Drainable plugins within the drain timeout
+func (m *DefaultLynxPluginManager) drain(ctx context.Context) {
+	var drainable []plugin.Plugin
+	for _, p := range m.pluginList {
+		if _, ok := p.(plugin.Drainable); ok {
+			drainable = append(drainable, p)
+		}
+	}
+	if len(drainable) == 0 {
+		return
+	}
+
+	timeout := defaultDrainTimeout
+	if Lynx() != nil {
+		if t := Lynx().bootConf.GetLynx().GetShutdown().GetDrainTimeout(); t != nil {
+			timeout = t.AsDuration()
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, p := range drainable {
+		wg.Add(1)
+		go func(p plugin.Plugin) {
+			defer wg.Done()
+			if err := p.(plugin.Drainable).Drain(ctx); err != nil {
+				Lynx().PluginHelper(p.Name()).Warnf("Plugin %v did not drain cleanly: %v", p.Name(), err)
+			}
+		}(p)
+	}
+	wg.Wait()
+	Lynx().Helper().Infof("Drained %v plugins in %v", len(drainable), time.Since(start))
+}
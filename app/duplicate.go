@@ -0,0 +1,28 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+	"sort"
+	"strings"
+)
+
+// checkDuplicates reports plugins sharing a name, the manager keeps one plugin per name so the others would be
+// silently dropped
+func checkDuplicates(plugins []plugin.Plugin) error {
+	types := make(map[string][]string)
+	for _, p := range plugins {
+		types[p.Name()] = append(types[p.Name()], fmt.Sprintf("%T", p))
+	}
+	var collisions []string
+	for name, t := range types {
+		if len(t) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%v (%v)", name, strings.Join(t, ", ")))
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+	sort.Strings(collisions)
+	return fmt.Errorf("duplicate plugin names: %v", strings.Join(collisions, "; "))
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphNode is a plugin in the dependency graph, Level is the load level it resolves to
+type GraphNode struct {
+	Name   string `json:"name"`
+	Level  int    `json:"level"`
+	Weight int    `json:"weight"`
+}
+
+// GraphEdge points from a plugin to one of its dependencies. Cyclic marks an edge closing a cycle, Missing an
+// edge to a plugin that isn't registered; both prevent the plugins from loading. Optional marks a present
+// optional dependency, absent ones are left out.
+type GraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Optional bool   `json:"optional,omitempty"`
+	Cyclic   bool   `json:"cyclic,omitempty"`
+	Missing  bool   `json:"missing,omitempty"`
+}
+
+// Graph is the resolved plugin dependency graph
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// DependencyGraph resolves the dependency graph of all plugins. Unlike loading, it doesn't stop at cycles or
+// unknown dependencies: the offending edges are flagged and the graph is returned together with an error
+// describing them.
+func (m *DefaultLynxPluginManager) DependencyGraph() (*Graph, error) {
+	deps := make(map[string][]string, len(m.pluginList))
+	optional := make(map[string]map[string]bool)
+	for _, p := range m.pluginList {
+		deps[p.Name()] = append([]string(nil), dependsOn(p)...)
+	}
+	for _, p := range m.pluginList {
+		for _, dep := range optionalDependsOn(p) {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if optional[p.Name()] == nil {
+				optional[p.Name()] = make(map[string]bool)
+			}
+			optional[p.Name()][dep] = true
+			deps[p.Name()] = append(deps[p.Name()], dep)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	level := make(map[string]int)
+	graph := &Graph{}
+	var problems []string
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		level[name] = 1
+		for _, dep := range deps[name] {
+			edge := GraphEdge{From: name, To: dep, Optional: optional[name][dep]}
+			if _, ok := deps[dep]; !ok {
+				edge.Missing = true
+				problems = append(problems, fmt.Sprintf("%v depends on unknown plugin %v", name, dep))
+			} else {
+				switch state[dep] {
+				case visiting:
+					edge.Cyclic = true
+					problems = append(problems, fmt.Sprintf("cyclic dependency %v -> %v", name, dep))
+				case unvisited:
+					visit(dep)
+				}
+				if !edge.Cyclic && level[dep]+1 > level[name] {
+					level[name] = level[dep] + 1
+				}
+			}
+			graph.Edges = append(graph.Edges, edge)
+		}
+		state[name] = done
+	}
+	for _, p := range m.pluginList {
+		if state[p.Name()] == unvisited {
+			visit(p.Name())
+		}
+	}
+
+	for _, p := range m.pluginList {
+		graph.Nodes = append(graph.Nodes, GraphNode{Name: p.Name(), Level: level[p.Name()], Weight: p.Weight()})
+	}
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Level < graph.Nodes[j].Level
+	})
+	if len(problems) > 0 {
+		return graph, fmt.Errorf("unresolvable plugin dependencies: %v", strings.Join(problems, "; "))
+	}
+	return graph, nil
+}
+
+// ToDOT renders the graph in the Graphviz DOT language, plugins of the same level share a rank and unresolvable
+// edges are drawn in red, e.g. lynx doctor graph | dot -Tsvg > plugins.svg
+func (g *Graph) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph plugins {\n  rankdir=BT;\n  node [shape=box];\n")
+	levels := make(map[int][]string)
+	var order []int
+	for _, n := range g.Nodes {
+		if _, ok := levels[n.Level]; !ok {
+			order = append(order, n.Level)
+		}
+		levels[n.Level] = append(levels[n.Level], n.Name)
+		fmt.Fprintf(&b, "  %q [label=%q];\n", n.Name, fmt.Sprintf("%v\nlevel %v", n.Name, n.Level))
+	}
+	for _, l := range order {
+		b.WriteString("  { rank=same;")
+		for _, name := range levels[l] {
+			fmt.Fprintf(&b, " %q;", name)
+		}
+		b.WriteString(" }\n")
+	}
+	for _, e := range g.Edges {
+		switch {
+		case e.Missing:
+			fmt.Fprintf(&b, "  %q -> %q [color=red, style=dashed, label=\"missing\"];\n", e.From, e.To)
+		case e.Cyclic:
+			fmt.Fprintf(&b, "  %q -> %q [color=red, label=\"cycle\"];\n", e.From, e.To)
+		case e.Optional:
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed];\n", e.From, e.To)
+		default:
+			fmt.Fprintf(&b, "  %q -> %q;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used when lynx.plugins.health is not configured
+const (
+	defaultHealthCheckTimeout = 5 * time.Second
+	defaultHealthConcurrency  = 8
+)
+
+// HealthSuspended is the health of a plugin taken offline with SuspendPlugin. It is intentional, so it neither
+// lowers the overall status nor degrades the plugins depending on it.
+const HealthSuspended = "suspended"
+
+// ErrHealthCheckTimeout is reported for plugins whose health check didn't finish in time
+var ErrHealthCheckTimeout = errors.New("health check timed out")
+
+// PluginHealth is the health of a single plugin. Self is the result of the plugin's own check, Effective also
+// accounts for its transitive dependencies: a plugin whose dependency is not healthy is at best degraded.
+type PluginHealth struct {
+	Name      string `json:"name"`
+	Self      string `json:"self"`
+	Effective string `json:"effective"`
+	Error     string `json:"error,omitempty"`
+	TimedOut  bool   `json:"timed_out,omitempty"`
+	// Causes names the dependencies that lowered the effective health
+	Causes []string `json:"causes,omitempty"`
+}
+
+// HealthSnapshot is the health of all plugins at one point in time, Status is the worst effective health
+type HealthSnapshot struct {
+	Status    string         `json:"status"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Plugins   []PluginHealth `json:"plugins"`
+}
+
+// healthRank orders the health states, lower is worse
+var healthRank = map[string]int{
+	plugin.HealthUnhealthy: 0,
+	plugin.HealthDegraded:  1,
+	plugin.HealthHealthy:   2,
+}
+
+func worseHealth(a, b string) string {
+	if healthRank[b] < healthRank[a] {
+		return b
+	}
+	return a
+}
+
+// CheckHealth runs the health checks of all plugins and propagates the results along the dependency graph.
+// Plugins without a health check are healthy once loaded, degraded while loading and unhealthy if they failed to
+// load or were skipped. Suspended plugins are not checked and reported as HealthSuspended.
+func (m *DefaultLynxPluginManager) CheckHealth(ctx context.Context) HealthSnapshot {
+	errs := m.checkAll(ctx)
+	snapshot := HealthSnapshot{
+		Status:    plugin.HealthHealthy,
+		CheckedAt: time.Now(),
+		Plugins:   make([]PluginHealth, len(m.pluginList)),
+	}
+	index := make(map[string]int, len(m.pluginList))
+	for i, p := range m.pluginList {
+		index[p.Name()] = i
+		result := &snapshot.Plugins[i]
+		result.Name = p.Name()
+		result.Self = plugin.HealthHealthy
+		if m.isSuspended(p.Name()) {
+			result.Self = HealthSuspended
+			continue
+		}
+		if err := errs[p.Name()]; err != nil {
+			result.Error = err.Error()
+			result.TimedOut = errors.Is(err, ErrHealthCheckTimeout)
+			result.Self = plugin.HealthUnhealthy
+			if plugin.IsDegraded(err) {
+				result.Self = plugin.HealthDegraded
+			}
+		}
+	}
+
+	// Resolve the effective health depth first, a dependency that isn't healthy degrades its dependents
+	resolving := make(map[string]bool)
+	var resolve func(i int) string
+	resolve = func(i int) string {
+		result := &snapshot.Plugins[i]
+		if result.Effective != "" || resolving[result.Name] {
+			return result.Effective
+		}
+		resolving[result.Name] = true
+		effective := result.Self
+		if effective == HealthSuspended {
+			result.Effective = effective
+			return effective
+		}
+		for _, dep := range dependsOn(m.pluginList[i]) {
+			j, ok := index[dep]
+			if !ok {
+				continue
+			}
+			if depHealth := resolve(j); depHealth != "" && depHealth != plugin.HealthHealthy && depHealth != HealthSuspended {
+				effective = worseHealth(effective, plugin.HealthDegraded)
+				result.Causes = append(result.Causes, dep)
+			}
+		}
+		result.Effective = effective
+		return effective
+	}
+	for i := range snapshot.Plugins {
+		if effective := resolve(i); effective != HealthSuspended {
+			snapshot.Status = worseHealth(snapshot.Status, effective)
+		}
+	}
+	return snapshot
+}
+
+// WaitHealthy blocks until the named plugin is loaded and its own health check passes, polling with a growing
+// interval. When ctx is done first it returns the last health error.
+func (m *DefaultLynxPluginManager) WaitHealthy(ctx context.Context, name string) error {
+	p, ok := m.pluginMap[name]
+	if !ok {
+		return fmt.Errorf("unknown plugin %v", name)
+	}
+	interval := 50 * time.Millisecond
+	for {
+		err := m.checkPluginHealth(ctx, p)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("plugin %v is not healthy: %w", name, err)
+		case <-time.After(interval):
+		}
+		if interval < 2*time.Second {
+			interval *= 2
+		}
+	}
+}
+
+// HealthReport runs the health checks of all plugins and returns the result keyed by plugin name, nil when the
+// plugin is healthy. A check that didn't finish in time returns an error wrapping ErrHealthCheckTimeout.
+// CheckHealth serves the same results as a JSON friendly HealthSnapshot.
+func (a *LynxApp) HealthReport() map[string]error {
+	return a.PlugManager().HealthReport(context.Background())
+}
+
+// HealthReport runs the health checks of all plugins, see LynxApp.HealthReport
+func (m *DefaultLynxPluginManager) HealthReport(ctx context.Context) map[string]error {
+	return m.checkAll(ctx)
+}
+
+// checkAll checks the plugins in parallel, bounded by lynx.plugins.health.max_concurrency, each check bounded by
+// lynx.plugins.health.check_timeout
+func (m *DefaultLynxPluginManager) checkAll(ctx context.Context) map[string]error {
+	conf := Lynx().pluginsConf().GetHealth()
+	concurrency := int(conf.GetMaxConcurrency())
+	if concurrency <= 0 {
+		concurrency = defaultHealthConcurrency
+	}
+	timeout := defaultHealthCheckTimeout
+	if t := conf.GetCheckTimeout(); t != nil {
+		timeout = t.AsDuration()
+	}
+
+	errs := make(map[string]error, len(m.pluginList))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for _, p := range m.pluginList {
+		// Suspended plugins are offline on purpose, checking them would only report noise
+		if m.isSuspended(p.Name()) {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(p plugin.Plugin) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			err := m.checkPluginHealth(ctx, p)
+			mu.Lock()
+			errs[p.Name()] = err
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return errs
+}
+
+// checkPluginHealth returns nil when the plugin is loaded and healthy. A plugin that is still loading is degraded,
+// and a check that outlives ctx is reported as timed out even when it ignores ctx.
+func (m *DefaultLynxPluginManager) checkPluginHealth(ctx context.Context, p plugin.Plugin) error {
+	status := PluginPending
+	for _, pp := range m.progress.snapshot().Plugins {
+		if pp.Name == p.Name() {
+			status = pp.Status
+		}
+	}
+	switch status {
+	case PluginLoaded:
+	case PluginFailed, PluginSkipped:
+		return fmt.Errorf("plugin is %v", status)
+	default:
+		return plugin.Degraded(fmt.Errorf("plugin is %v", status))
+	}
+
+	checker, ok := p.(plugin.HealthChecker)
+	if !ok {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.CheckHealth(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrHealthCheckTimeout, ctx.Err())
+	}
+}
+
+// HealthHandler serves the health snapshot as JSON, answering 503 while any plugin is unhealthy,
+// e.g. http.GetServer().Handle("/health", app.HealthHandler())
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snapshot := Lynx().PlugManager().CheckHealth(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if snapshot.Status == plugin.HealthUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(snapshot)
+	})
+}
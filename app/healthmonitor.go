@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// HealthChange reports a plugin whose health changed between two polls of the health monitor
+type HealthChange struct {
+	Plugin         string `json:"plugin"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+	// ConsecutiveFailures is the number of failed checks in a row, zero when the plugin recovered
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Error               string `json:"error,omitempty"`
+}
+
+// OnHealthChange registers a hook the health monitor calls when a plugin turns unhealthy or recovers,
+// polls that don't change the health don't call it
+func (m *DefaultLynxPluginManager) OnHealthChange(fn func(HealthChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.healthHooks = append(m.healthHooks, fn)
+}
+
+// startHealthMonitor polls the plugin health every lynx.plugins.health.poll_interval until stopHealthMonitor
+func (m *DefaultLynxPluginManager) startHealthMonitor() {
+	conf := Lynx().pluginsConf().GetHealth()
+	interval := conf.GetPollInterval().AsDuration()
+	if interval <= 0 {
+		return
+	}
+	threshold := int(conf.GetFailureThreshold())
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.healthStop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	m.healthStop = stop
+	go func() {
+		status := make(map[string]string)
+		failures := make(map[string]int)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			for name, err := range m.checkAll(context.Background()) {
+				previous, ok := status[name]
+				if !ok {
+					previous = plugin.HealthHealthy
+				}
+				change := HealthChange{Plugin: name, Status: plugin.HealthHealthy, PreviousStatus: previous}
+				if err == nil {
+					failures[name] = 0
+				} else {
+					failures[name]++
+					if failures[name] < threshold {
+						continue
+					}
+					change.Status = plugin.HealthUnhealthy
+					if plugin.IsDegraded(err) {
+						change.Status = plugin.HealthDegraded
+					}
+					change.Error = err.Error()
+				}
+				change.ConsecutiveFailures = failures[name]
+				status[name] = change.Status
+				if change.Status != previous {
+					m.notifyHealthChange(change)
+				}
+			}
+		}
+	}()
+}
+
+func (m *DefaultLynxPluginManager) stopHealthMonitor() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.healthStop != nil {
+		close(m.healthStop)
+		m.healthStop = nil
+	}
+}
+
+func (m *DefaultLynxPluginManager) notifyHealthChange(change HealthChange) {
+	if change.Status == plugin.HealthHealthy {
+		Lynx().PluginHelper(change.Plugin).Infof("Plugin %v recovered, previously %v", change.Plugin, change.PreviousStatus)
+	} else {
+		Lynx().PluginHelper(change.Plugin).Warnf("Plugin %v turned %v after %v failed checks: %v",
+			change.Plugin, change.Status, change.ConsecutiveFailures, change.Error)
+	}
+	m.mu.Lock()
+	hooks := append([]func(HealthChange){}, m.healthHooks...)
+	m.mu.Unlock()
+	for _, fn := range hooks {
+		fn(change)
+	}
+}
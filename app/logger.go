@@ -17,19 +17,7 @@ func (a *LynxApp) InitLogger() {
 	log.Infof("Lynx Log component loading")
 
 	// 初始化日志记录器，使用标准输出作为日志输出，添加默认的时间戳、调用者信息、服务 ID、服务名称、服务版本、跟踪 ID 和跨度 ID
-	a.logger = log.With(
-		log.NewStdLogger(os.Stdout),
-		"ts", log.DefaultTimestamp,
-		"caller", log.DefaultCaller,
-		"service.id", Host(),
-		"service.name", Name(),
-		"service.version", Version(),
-		"trace.id", tracing.TraceID(),
-		"span.id", tracing.SpanID(),
-	)
-
-	// 初始化日志助手，用于更方便地记录日志
-	a.dfLog = log.NewHelper(a.logger)
+	a.wrapLogger()
 
 	// 打印日志，指示 Lynx 日志组件加载成功
 	log.Info("Lynx Log component loaded successfully")
@@ -64,3 +52,46 @@ func (a *LynxApp) Helper() *log.Helper {
 func (a *LynxApp) Logger() log.Logger {
 	return a.logger
 }
+
+// SetLogger replaces the sink Lynx logs to, e.g. a JSON or OpenTelemetry logger, the service and trace fields
+// are added to it. Loggers obtained before keep writing to the previous sink.
+func (a *LynxApp) SetLogger(sink log.Logger) {
+	a.sink = sink
+	if a.logger != nil {
+		a.wrapLogger()
+	}
+}
+
+// wrapLogger creates the application logger from the sink, adding the timestamp, caller, service and trace fields
+func (a *LynxApp) wrapLogger() {
+	sink := a.sink
+	if sink == nil {
+		sink = log.NewStdLogger(os.Stdout)
+	}
+	a.logger = log.With(
+		sink,
+		"ts", log.DefaultTimestamp,
+		"caller", log.DefaultCaller,
+		"service.id", Host(),
+		"service.name", Name(),
+		"service.version", Version(),
+		"trace.id", tracing.TraceID(),
+		"span.id", tracing.SpanID(),
+	)
+	a.dfLog = log.NewHelper(a.logger)
+}
+
+// PluginLogger returns a logger attributing every entry to the named plugin through the plugin_id field, followed by
+// the given key value pairs
+func (a *LynxApp) PluginLogger(name string, kv ...interface{}) log.Logger {
+	logger := a.logger
+	if logger == nil {
+		logger = log.GetLogger()
+	}
+	return log.With(logger, append([]interface{}{"plugin_id", name}, kv...)...)
+}
+
+// PluginHelper is PluginLogger wrapped in a helper
+func (a *LynxApp) PluginHelper(name string, kv ...interface{}) *log.Helper {
+	return log.NewHelper(a.PluginLogger(name, kv...))
+}
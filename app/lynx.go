@@ -6,6 +6,7 @@ import (
 	"github.com/go-lynx/lynx/conf"
 	"github.com/go-lynx/lynx/plugin"
 	"os"
+	"sync"
 )
 
 var (
@@ -20,9 +21,16 @@ type LynxApp struct {
 	cert          Cert
 	logger        log.Logger
 	globalConf    config.Config
+	bootConf      *conf.Bootstrap
 	controlPlane  ControlPlane
 	pluginManager LynxPluginManager
 
+	// lbs holds the load balancer of every service resolved with PickInstance, created on first use
+	lbMu sync.Mutex
+	lbs  map[string]LoadBalancer
+
+	// sink is the logger set with SetLogger, standard output when nil
+	sink  log.Logger
 	dfLog *log.Helper
 }
 
@@ -70,6 +78,8 @@ func NewApp(c config.Config, p ...plugin.Plugin) *LynxApp {
 		version: bootConf.Lynx.Application.Version,
 		// 设置全局配置对象
 		globalConf: c,
+		// 保存启动配置，供框架自身的配置项使用
+		bootConf: &bootConf,
 		// 创建一个新的 LynxPluginManager 实例，并传入插件列表
 		pluginManager: NewLynxPluginManager(p...),
 		// 设置控制平面为本地控制平面实例
@@ -91,6 +101,14 @@ func (a *LynxApp) GlobalConfig() config.Config {
 	return a.globalConf
 }
 
+// pluginsConf returns the framework level plugin settings from the bootstrap configuration
+func (a *LynxApp) pluginsConf() *conf.Plugins {
+	if a == nil || a.bootConf == nil {
+		return nil
+	}
+	return a.bootConf.GetLynx().GetPlugins()
+}
+
 func (a *LynxApp) setGlobalConfig(c config.Config) {
 	// Close the last configuration
 	if a.globalConf != nil {
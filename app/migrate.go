@@ -0,0 +1,69 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+	"strings"
+)
+
+// migrateConfig runs the config migrations of a plugin on its configuration value. The value is returned as is
+// when the plugin declares no migrations or none of them applies.
+func migrateConfig(p plugin.Plugin, v config.Value) (config.Value, error) {
+	migrator, ok := p.(plugin.ConfigMigrator)
+	if !ok {
+		return v, nil
+	}
+	var raw map[string]interface{}
+	if err := v.Scan(&raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+
+	migrated := false
+	for _, migration := range migrator.ConfigMigrations() {
+		changed, err := migration.Migrate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("migrate %v config: %v: %w", p.Name(), migration.Description, err)
+		}
+		if changed {
+			migrated = true
+			if Lynx() != nil && Lynx().Helper() != nil {
+				Lynx().Helper().Warnf("Deprecated %v configuration under %v was migrated, please update it: %v",
+					p.Name(), p.ConfPrefix(), migration.Description)
+			}
+		}
+	}
+	if !migrated {
+		return v, nil
+	}
+	return staticValue(p.ConfPrefix(), raw)
+}
+
+// pluginConfig returns the configuration of p in c, migrated the same way it is when the plugin is loaded
+func pluginConfig(p plugin.Plugin, c config.Config) (config.Value, error) {
+	return migrateConfig(p, c.Value(p.ConfPrefix()))
+}
+
+// staticValue turns a configuration map back into a config.Value found under prefix
+func staticValue(prefix string, raw map[string]interface{}) (config.Value, error) {
+	var root interface{} = raw
+	keys := strings.Split(prefix, ".")
+	for i := len(keys) - 1; i >= 0; i-- {
+		root = map[string]interface{}{keys[i]: root}
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	c := config.New(config.WithSource(NewStaticSource(prefix, data, "json")))
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	// Values stay readable after the config stops watching its source
+	defer c.Close()
+	return c.Value(prefix), nil
+}
@@ -1,26 +1,75 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/transport"
 	"github.com/go-lynx/lynx/factory"
 	"github.com/go-lynx/lynx/plugin"
 	"sort"
+	"sync"
+	"time"
 )
 
 type LynxPluginManager interface {
 	LoadPlugins(config.Config)
 	UnloadPlugins()
+	UnloadPluginsContext(ctx context.Context) error
 	LoadPluginsByName([]string, config.Config)
 	UnloadPluginsByName([]string)
 	GetPlugin(name string) plugin.Plugin
 	PreparePlug(config config.Config) []string
+	Heartbeat(name string, progress string)
+	StartupProgress() StartupProgress
+	CheckReadiness(ctx context.Context) ReadinessReport
+	Servers() []transport.Server
+	ReloadConfig(next config.Config) error
+	Quiesce(ctx context.Context) error
+	Resume(ctx context.Context) error
+	CheckHealth(ctx context.Context) HealthSnapshot
+	HealthReport(ctx context.Context) map[string]error
+	OnHealthChange(fn func(HealthChange))
+	OnConfigChange(fn func(ConfigChange))
+	RestartPlugin(name string, force bool) error
+	DependencyGraph() (*Graph, error)
+	WaitHealthy(ctx context.Context, name string) error
+	LastShutdown() ShutdownSummary
+	ValidateConfig(c config.Config) []error
+	SuspendPlugin(ctx context.Context, name string) error
+	ResumePlugin(ctx context.Context, name string) error
+	Drain(ctx context.Context)
 }
 
 type DefaultLynxPluginManager struct {
 	pluginMap  map[string]plugin.Plugin
 	pluginList []plugin.Plugin
 	factory    factory.PluginFactory
+
+	// heartbeats holds the progress channel of every plugin currently being loaded, abandoned the plugins whose
+	// load the watchdog gave up on and that haven't returned yet
+	heartbeats map[string]chan string
+	abandoned  map[string]bool
+	mu         sync.Mutex
+	progress   *startupTracker
+	// reloadMu serializes configuration reloads
+	reloadMu sync.Mutex
+	// lastShutdown is the summary of the last unload
+	lastShutdown ShutdownSummary
+	// healthHooks are called on health changes, healthStop stops the running health monitor
+	healthHooks []func(HealthChange)
+	healthStop  chan struct{}
+	// watchedConf is the configuration watched for plugin changes, configTimers debounce the changes per plugin
+	watchedConf  config.Config
+	configTimers map[string]*time.Timer
+	configHooks  []func(ConfigChange)
+	// suspended holds the plugins taken offline with SuspendPlugin, suspendMu serializes suspending and resuming
+	// single plugins and the whole application
+	suspended map[string]bool
+	suspendMu sync.Mutex
+	// drained is set once the Drainable plugins were drained, drainDuration is the time it took
+	drained       bool
+	drainDuration time.Duration
 }
 
 func NewLynxPluginManager(p ...plugin.Plugin) LynxPluginManager {
@@ -28,6 +77,8 @@ func NewLynxPluginManager(p ...plugin.Plugin) LynxPluginManager {
 		pluginList: make([]plugin.Plugin, 0),
 		factory:    factory.GlobalPluginFactory(),
 		pluginMap:  make(map[string]plugin.Plugin),
+		heartbeats: make(map[string]chan string),
+		progress:   newStartupTracker(),
 	}
 
 	// Manually set pluginList
@@ -55,14 +106,7 @@ func (m *DefaultLynxPluginManager) TopologicalSort(plugins []plugin.Plugin) ([]P
 	// Then, build the adjacency list for the graph.
 	graph := make(map[string][]string)
 	for _, p := range plugins {
-		var on []string
-		if Lynx() != nil && Lynx().GlobalConfig() != nil {
-			on = p.DependsOn(Lynx().GlobalConfig().Value(p.ConfPrefix()))
-		} else {
-			on = p.DependsOn(nil)
-		}
-
-		for _, dep := range on {
+		for _, dep := range dependsOn(p) {
 			// If the dependency exists, add it to the graph.
 			if _, ok := nameToPlugin[dep]; ok {
 				graph[p.Name()] = append(graph[p.Name()], dep)
@@ -70,6 +114,12 @@ func (m *DefaultLynxPluginManager) TopologicalSort(plugins []plugin.Plugin) ([]P
 				panic(fmt.Sprintf("Plugin %s depends on unknown plugin %s", p.Name(), dep))
 			}
 		}
+		// Optional dependencies only order the plugins when they are present
+		for _, dep := range optionalDependsOn(p) {
+			if _, ok := nameToPlugin[dep]; ok {
+				graph[p.Name()] = append(graph[p.Name()], dep)
+			}
+		}
 	}
 
 	// Perform the topological sort.
@@ -113,6 +163,26 @@ func (m *DefaultLynxPluginManager) TopologicalSort(plugins []plugin.Plugin) ([]P
 	return result, nil
 }
 
+// dependsOn returns the dependencies of a plugin under the current global configuration
+func dependsOn(p plugin.Plugin) []string {
+	if Lynx() != nil && Lynx().GlobalConfig() != nil {
+		return p.DependsOn(Lynx().GlobalConfig().Value(p.ConfPrefix()))
+	}
+	return p.DependsOn(nil)
+}
+
+// optionalDependsOn returns the optional dependencies of a plugin under the current global configuration
+func optionalDependsOn(p plugin.Plugin) []string {
+	o, ok := p.(plugin.OptionalDependent)
+	if !ok {
+		return nil
+	}
+	if Lynx() != nil && Lynx().GlobalConfig() != nil {
+		return o.OptionalDependsOn(Lynx().GlobalConfig().Value(p.ConfPrefix()))
+	}
+	return o.OptionalDependsOn(nil)
+}
+
 func contains(slice []PluginWithLevel, item plugin.Plugin) bool {
 	for _, v := range slice {
 		if v.Plugin == item {
@@ -123,36 +193,47 @@ func contains(slice []PluginWithLevel, item plugin.Plugin) bool {
 }
 
 func (m *DefaultLynxPluginManager) LoadPlugins(conf config.Config) {
+	if err := checkDuplicates(m.pluginList); err != nil {
+		Lynx().Helper().Errorf("Exception in preparing pluginList : %v", err)
+		panic(err)
+	}
 	plugins, err := m.TopologicalSort(m.pluginList)
 	if err != nil {
 		Lynx().Helper().Errorf("Exception in topological sorting pluginList :", err)
 		panic(err)
 	}
 
-	size := len(plugins)
-	for i := 0; i < size; i++ {
-		_, err := plugins[i].Load(conf.Value(plugins[i].ConfPrefix()))
-		if err != nil {
-			Lynx().Helper().Errorf("Exception in initializing %v plugin :", plugins[i].Name(), err)
-			panic(err)
-		}
-	}
+	// Subscriptions dialed while loading already pick instances with the configured policy
+	Lynx().applyLoadBalancer()
+	m.loadSorted(plugins, conf)
+	m.startHealthMonitor()
+	m.watchConfig()
 }
 
+// UnloadPlugins unloads all plugins phase by phase within the configured shutdown budget, see plugin.ShutdownPhases
 func (m *DefaultLynxPluginManager) UnloadPlugins() {
-	size := len(m.pluginList)
-	for i := 0; i < size; i++ {
-		err := m.pluginList[i].Unload()
-		if err != nil {
-			Lynx().Helper().Errorf("Exception in uninstalling %v plugin", m.pluginList[i].Name(), err)
-		}
-	}
+	ctx, cancel := shutdownContext()
+	defer cancel()
+	_ = m.UnloadPluginsContext(ctx)
+}
+
+// UnloadPluginsContext unloads all plugins phase by phase, plugins that don't depend on each other are unloaded
+// in parallel. Plugins that haven't finished when ctx is done are reported in the returned error.
+func (m *DefaultLynxPluginManager) UnloadPluginsContext(ctx context.Context) error {
+	m.stopHealthMonitor()
+	m.stopConfigWatch()
+	m.Drain(ctx)
+	return m.unloadBatchesContext(ctx, m.unloadBatches(m.pluginList))
 }
 
 func (m *DefaultLynxPluginManager) LoadPluginsByName(name []string, conf config.Config) {
 	if name == nil || len(name) == 0 {
 		return
 	}
+	if err := checkDuplicates(m.pluginList); err != nil {
+		Lynx().Helper().Errorf("Exception in preparing pluginList : %v", err)
+		panic(err)
+	}
 
 	var pluginList []plugin.Plugin
 	for i := 0; i < len(name); i++ {
@@ -166,24 +247,80 @@ func (m *DefaultLynxPluginManager) LoadPluginsByName(name []string, conf config.
 		panic(err)
 	}
 
+	m.loadSorted(plugins, conf)
+}
+
+// loadSorted loads topologically sorted plugins one after another while tracking the startup progress
+func (m *DefaultLynxPluginManager) loadSorted(plugins []PluginWithLevel, conf config.Config) {
+	stop := m.progress.begin(plugins)
+	defer stop()
+	checkShutdownPhases(plugins)
+
 	for i := 0; i < len(plugins); i++ {
-		_, err := plugins[i].Load(conf.Value(plugins[i].ConfPrefix()))
+		m.progress.update(plugins[i].Name(), PluginLoading, nil)
+		err := m.waitDependencies(plugins[i].Plugin)
+		// Waiting for the readiness of other plugins is not part of the own startup time
+		start := time.Now()
+		if err == nil {
+			err = m.loadPlugin(plugins[i].Plugin, conf)
+			observeSince(initializeDuration, plugins[i].Name(), start)
+		}
 		if err != nil {
-			Lynx().Helper().Errorf("Exception in initializing %v plugin :", plugins[i].Name(), err)
+			m.progress.update(plugins[i].Name(), PluginFailed, err)
+			Lynx().PluginHelper(plugins[i].Name()).Errorf("Exception in initializing %v plugin : %v", plugins[i].Name(), err)
+			m.skipDependents(plugins[i].Name(), plugins[i+1:])
+			Lynx().Helper().Errorf("Lynx startup stalled: %v", m.progress.snapshot())
 			panic(err)
 		}
+		m.preWarm(plugins[i].Plugin)
+		observeSince(startDuration, plugins[i].Name(), start)
+		m.progress.update(plugins[i].Name(), PluginLoaded, nil)
+	}
+}
+
+// skipDependents marks the pending plugins that directly or transitively depend on a failed plugin as skipped,
+// so the startup report names the root cause instead of leaving them pending
+func (m *DefaultLynxPluginManager) skipDependents(failed string, pending []PluginWithLevel) {
+	causes := map[string]string{failed: failed}
+	// Dependencies always come first in the sorted order, one pass reaches every transitive dependent
+	for _, p := range pending {
+		for _, dep := range dependsOn(p.Plugin) {
+			if cause, ok := causes[dep]; ok {
+				causes[p.Name()] = cause
+				m.progress.update(p.Name(), PluginSkipped, fmt.Errorf("skipped due to failed dependency %v", cause))
+				break
+			}
+		}
 	}
 }
 
 func (m *DefaultLynxPluginManager) UnloadPluginsByName(name []string) {
+	targets := make(map[string]bool, len(name))
 	for i := 0; i < len(name); i++ {
-		err := m.pluginMap[name[i]].Unload()
-		if err != nil {
-			Lynx().Helper().Errorf("Exception in uninstalling %v plugin", name[i], err)
+		targets[name[i]] = true
+	}
+	var plugins []plugin.Plugin
+	for _, p := range m.pluginList {
+		if targets[p.Name()] {
+			plugins = append(plugins, p)
 		}
 	}
+	ctx, cancel := shutdownContext()
+	defer cancel()
+	_ = m.unloadBatchesContext(ctx, m.unloadBatches(plugins))
 }
 
 func (m *DefaultLynxPluginManager) GetPlugin(name string) plugin.Plugin {
 	return m.pluginMap[name]
 }
+
+// Servers collects the servers of all plugins that serve traffic, in the order the plugins were added
+func (m *DefaultLynxPluginManager) Servers() []transport.Server {
+	var servers []transport.Server
+	for _, p := range m.pluginList {
+		if provider, ok := p.(plugin.ServerProvider); ok {
+			servers = append(servers, provider.Servers()...)
+		}
+	}
+	return servers
+}
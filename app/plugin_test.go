@@ -1,10 +1,19 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-lynx/lynx/plugin"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type MockPlugin struct {
@@ -12,6 +21,7 @@ type MockPlugin struct {
 	depends    []string
 	weight     int
 	confPrefix string
+	phase      string
 }
 
 func (m *MockPlugin) Name() string {
@@ -30,6 +40,10 @@ func (m *MockPlugin) ConfPrefix() string {
 	return m.confPrefix
 }
 
+func (m *MockPlugin) ShutdownPhase() string {
+	return m.phase
+}
+
 func (m *MockPlugin) Load(c config.Value) (plugin.Plugin, error) {
 	return m, nil
 }
@@ -68,3 +82,662 @@ func TestTopologicalSort(t *testing.T) {
 		}
 	}
 }
+
+func TestUnloadOrder(t *testing.T) {
+	manager := NewLynxPluginManager().(*DefaultLynxPluginManager)
+
+	db := &MockPlugin{name: "db", phase: plugin.PhaseStores}
+	cache := &MockPlugin{name: "cache", phase: plugin.PhaseStores}
+	worker := &MockPlugin{name: "worker", depends: []string{"db"}}
+	server := &MockPlugin{name: "server", depends: []string{"cache", "worker"}, phase: plugin.PhaseIngress}
+	cacheClient := &MockPlugin{name: "cacheClient", depends: []string{"cache"}, phase: plugin.PhaseStores}
+
+	result := manager.unloadOrder([]plugin.Plugin{db, cache, worker, server, cacheClient})
+
+	expectedOrder := []string{"server", "worker", "cacheClient", "cache", "db"}
+	for i, p := range result {
+		if p.Name() != expectedOrder[i] {
+			t.Errorf("Expected order %v, but got %v", expectedOrder[i], p.Name())
+		}
+	}
+}
+
+// messageLogger collects the logged messages
+type messageLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *messageLogger) Log(_ log.Level, keyvals ...interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprint(keyvals...))
+	return nil
+}
+
+func (l *messageLogger) count(substr string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, m := range l.messages {
+		if strings.Contains(m, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestUnknownShutdownPhase(t *testing.T) {
+	worker := &MockPlugin{name: "worker", phase: "later"}
+	manager := newTestApp(t, "", worker)
+	logger := &messageLogger{}
+	Lynx().SetLogger(logger)
+	manager.LoadPlugins(Lynx().GlobalConfig())
+	defer manager.stopHealthMonitor()
+
+	for i := 0; i < 3; i++ {
+		if phase := shutdownPhase(worker); phase != plugin.PhaseWorkers {
+			t.Errorf("Expected the unknown phase to fall back to %v, but got %v", plugin.PhaseWorkers, phase)
+		}
+	}
+	manager.UnloadPlugins()
+	if n := logger.count("Unknown shutdown phase later"); n != 1 {
+		t.Errorf("Expected the unknown phase to be reported once, but it was reported %v times", n)
+	}
+}
+
+type unhealthyPlugin struct {
+	MockPlugin
+}
+
+func (u *unhealthyPlugin) CheckHealth(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealthPropagation(t *testing.T) {
+	manager := NewLynxPluginManager().(*DefaultLynxPluginManager)
+
+	db := &unhealthyPlugin{MockPlugin{name: "db"}}
+	worker := &MockPlugin{name: "worker", depends: []string{"db"}}
+	server := &MockPlugin{name: "server", depends: []string{"worker"}}
+	cache := &MockPlugin{name: "cache"}
+	manager.pluginList = []plugin.Plugin{db, worker, server, cache}
+
+	sorted, err := manager.TopologicalSort(manager.pluginList)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager.progress.begin(sorted)()
+	for _, p := range sorted {
+		manager.progress.update(p.Name(), PluginLoaded, nil)
+	}
+
+	snapshot := manager.CheckHealth(context.Background())
+	if snapshot.Status != plugin.HealthUnhealthy {
+		t.Errorf("Expected status %v, but got %v", plugin.HealthUnhealthy, snapshot.Status)
+	}
+	expected := map[string][2]string{
+		"db":     {plugin.HealthUnhealthy, plugin.HealthUnhealthy},
+		"worker": {plugin.HealthHealthy, plugin.HealthDegraded},
+		"server": {plugin.HealthHealthy, plugin.HealthDegraded},
+		"cache":  {plugin.HealthHealthy, plugin.HealthHealthy},
+	}
+	for _, p := range snapshot.Plugins {
+		if p.Self != expected[p.Name][0] || p.Effective != expected[p.Name][1] {
+			t.Errorf("Expected %v to be %v/%v, but got %v/%v", p.Name, expected[p.Name][0], expected[p.Name][1], p.Self, p.Effective)
+		}
+	}
+}
+
+type migratingPlugin struct {
+	MockPlugin
+}
+
+func (m *migratingPlugin) ConfigMigrations() []plugin.ConfigMigration {
+	return []plugin.ConfigMigration{
+		{
+			Description: "address was renamed to addr",
+			Migrate: func(c map[string]interface{}) (bool, error) {
+				v, ok := c["address"]
+				if !ok {
+					return false, nil
+				}
+				delete(c, "address")
+				c["addr"] = v
+				return true, nil
+			},
+		},
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	p := &migratingPlugin{MockPlugin{name: "mock", confPrefix: "lynx.mock"}}
+	old, err := staticValue("lynx.mock", map[string]interface{}{"address": "127.0.0.1:6379"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	v, err := migrateConfig(p, old)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var c struct {
+		Addr string `json:"addr"`
+	}
+	if err := v.Scan(&c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Addr != "127.0.0.1:6379" {
+		t.Errorf("Expected migrated addr 127.0.0.1:6379, but got %v", c.Addr)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	nested := map[string]interface{}{"value": "ok"}
+	for i := 0; i < defaultMaxConfigDepth; i++ {
+		nested = map[string]interface{}{"level": nested}
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"lynx":   map[string]interface{}{"http": map[string]interface{}{"addr": ":8000"}},
+		"nested": nested,
+		"blob":   strings.Repeat("x", defaultMaxConfigValueSize+1),
+	})
+	c := config.New(config.WithSource(&staticSource{kv: &config.KeyValue{Key: "test", Value: data, Format: "json"}}))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	err := CheckConfig(c)
+	limitErr, ok := err.(*ConfigLimitError)
+	if !ok {
+		t.Fatalf("Expected a ConfigLimitError, but got %v", err)
+	}
+	if len(limitErr.Violations) != 2 {
+		t.Errorf("Expected 2 violations, but got %v", limitErr.Violations)
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	manager := NewLynxPluginManager().(*DefaultLynxPluginManager)
+	manager.pluginList = []plugin.Plugin{
+		&MockPlugin{name: "A"},
+		&MockPlugin{name: "B", depends: []string{"A", "C"}},
+		&MockPlugin{name: "C", depends: []string{"B"}},
+		&MockPlugin{name: "D", depends: []string{"X"}},
+	}
+
+	graph, err := manager.DependencyGraph()
+	if err == nil {
+		t.Errorf("Expected an error for the cycle and the missing plugin")
+	}
+	var cyclic, missing int
+	for _, e := range graph.Edges {
+		if e.Cyclic {
+			cyclic++
+		}
+		if e.Missing {
+			missing++
+		}
+	}
+	if cyclic != 1 || missing != 1 || len(graph.Edges) != 4 {
+		t.Errorf("Expected 4 edges with 1 cyclic and 1 missing, but got %+v", graph.Edges)
+	}
+	if !strings.Contains(graph.ToDOT(), `"C" -> "B" [color=red, label="cycle"]`) {
+		t.Errorf("Expected the cycle edge in %v", graph.ToDOT())
+	}
+}
+
+type optionalPlugin struct {
+	MockPlugin
+	optional []string
+}
+
+func (o *optionalPlugin) OptionalDependsOn(config.Value) []string {
+	return o.optional
+}
+
+func TestOptionalDependency(t *testing.T) {
+	manager := NewLynxPluginManager().(*DefaultLynxPluginManager)
+	tracer := &MockPlugin{name: "tracer", weight: 1}
+	db := &optionalPlugin{MockPlugin: MockPlugin{name: "db", weight: 2}, optional: []string{"tracer", "metrics"}}
+	manager.pluginList = []plugin.Plugin{db, tracer}
+
+	sorted, err := manager.TopologicalSort(manager.pluginList)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sorted[0].Name() != "tracer" || sorted[1].Name() != "db" {
+		t.Errorf("Expected tracer to load before db, but got %v, %v", sorted[0].Name(), sorted[1].Name())
+	}
+
+	c := config.New(config.WithSource(&staticSource{kv: &config.KeyValue{Key: "test", Value: []byte("{}"), Format: "json"}}))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+	manager.LoadPlugins(c)
+	for _, p := range manager.StartupProgress().Plugins {
+		if p.Status != PluginLoaded {
+			t.Errorf("Expected %v to be loaded despite the absent optional dependency, but it is %v", p.Name, p.Status)
+		}
+	}
+}
+
+func TestGetPlugin(t *testing.T) {
+	a := &LynxApp{pluginManager: NewLynxPluginManager(&MockPlugin{name: "db"}, &optionalPlugin{MockPlugin: MockPlugin{name: "tracer"}})}
+	if p, err := GetPlugin[*MockPlugin](a, "db"); err != nil || p.Name() != "db" {
+		t.Fatalf("expected db plugin, got %v %v", p, err)
+	}
+	if _, err := GetPlugin[*MockPlugin](a, "tracer"); err == nil {
+		t.Fatal("expected a type mismatch error")
+	}
+	if _, err := GetPlugin[*MockPlugin](a, "redis"); err == nil {
+		t.Fatal("expected an error for an unregistered plugin")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := []byte(`{"type": "object", "additionalProperties": false, "required": ["addr"],
+		"properties": {"addr": {"type": "string"}, "timeout": {"type": "string"}, "max_conn": {"type": "integer"}}}`)
+	var v interface{}
+	_ = json.Unmarshal([]byte(`{"adr": ":8000", "max_conn": 1.5}`), &v)
+	errs, err := validateSchema(schema, "lynx.http", v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// addr is missing, adr is unknown and max_conn is not an integer
+	if len(errs) != 3 {
+		t.Errorf("Expected 3 violations, but got %v", errs)
+	}
+}
+
+func TestDuplicatePlugins(t *testing.T) {
+	manager := newTestApp(t, "",
+		&MockPlugin{name: "db"},
+		&MockPlugin{name: "redis"},
+		&optionalPlugin{MockPlugin: MockPlugin{name: "db"}},
+	)
+	want := "duplicate plugin names: db (*app.MockPlugin, *app.optionalPlugin)"
+	load := func(name string, fn func()) {
+		defer func() {
+			r := recover()
+			if err, ok := r.(error); !ok || err.Error() != want {
+				t.Errorf("Expected %v to fail with %q, but got %v", name, want, r)
+			}
+		}()
+		fn()
+	}
+	load("LoadPlugins", func() { manager.LoadPlugins(Lynx().GlobalConfig()) })
+	load("LoadPluginsByName", func() { manager.LoadPluginsByName([]string{"redis"}, Lynx().GlobalConfig()) })
+	load("PreparePlug", func() { manager.PreparePlug(Lynx().GlobalConfig()) })
+	if len(manager.StartupProgress().Plugins) != 0 {
+		t.Errorf("Expected no plugin to be loaded, but got %v", manager.StartupProgress())
+	}
+}
+
+type readinessPlugin struct {
+	MockPlugin
+	checked bool
+}
+
+func (r *readinessPlugin) ReadinessDependencies() []plugin.ReadinessDependency {
+	return []plugin.ReadinessDependency{{Name: "db", Check: func(ctx context.Context) error {
+		r.checked = true
+		return nil
+	}}}
+}
+
+func TestReadinessUnloaded(t *testing.T) {
+	manager := NewLynxPluginManager().(*DefaultLynxPluginManager)
+	p := &readinessPlugin{MockPlugin: MockPlugin{name: "db"}}
+	manager.pluginList = []plugin.Plugin{p}
+
+	report := manager.CheckReadiness(context.Background())
+	if report.Ready || p.checked {
+		t.Errorf("Expected an unloaded plugin to be not ready without checking its dependencies, got %+v", report)
+	}
+	if report.Plugins[0].Status != PluginPending {
+		t.Errorf("Expected status %v, but got %v", PluginPending, report.Plugins[0].Status)
+	}
+}
+
+// newTestApp makes a Lynx application the global one for the duration of a test, lynx holds the JSON fields of the
+// lynx section besides the application. Logging is discarded.
+func newTestApp(t *testing.T, lynx string, p ...plugin.Plugin) *DefaultLynxPluginManager {
+	data := `{"lynx": {"application": {"name": "test", "version": "v1"}`
+	if lynx != "" {
+		data += ", " + lynx
+	}
+	data += "}}"
+	c := config.New(config.WithSource(&staticSource{kv: &config.KeyValue{Key: "test", Value: []byte(data), Format: "json"}}))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a := NewApp(c)
+	if a == nil {
+		t.Fatalf("Invalid test configuration %v", data)
+	}
+	a.SetLogger(log.NewStdLogger(io.Discard))
+	a.wrapLogger()
+	manager := a.PlugManager().(*DefaultLynxPluginManager)
+	for _, plug := range p {
+		manager.pluginList = append(manager.pluginList, plug)
+		manager.pluginMap[plug.Name()] = plug
+	}
+	t.Cleanup(func() {
+		c.Close()
+		lynxApp = nil
+	})
+	return manager
+}
+
+type countingPlugin struct {
+	MockPlugin
+	loads, unloads int
+}
+
+func (c *countingPlugin) Load(config.Value) (plugin.Plugin, error) {
+	c.loads++
+	return c, nil
+}
+
+func (c *countingPlugin) Unload() error {
+	c.unloads++
+	return nil
+}
+
+func TestRestartPlugin(t *testing.T) {
+	db := &countingPlugin{MockPlugin: MockPlugin{name: "db"}}
+	worker := &countingPlugin{MockPlugin: MockPlugin{name: "worker", depends: []string{"db"}}}
+	manager := newTestApp(t, "", db, worker)
+	manager.LoadPlugins(Lynx().GlobalConfig())
+	defer manager.stopHealthMonitor()
+
+	if err := manager.RestartPlugin("db", false); err == nil || !strings.Contains(err.Error(), "[worker]") {
+		t.Fatalf("Expected the restart to be refused while worker depends on db, but got %v", err)
+	}
+	if db.unloads != 0 {
+		t.Fatalf("Expected a refused restart to leave db loaded, but it was unloaded %v times", db.unloads)
+	}
+
+	if err := manager.SuspendPlugin(context.Background(), "db"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := manager.RestartPlugin("worker", false); err == nil || !strings.Contains(err.Error(), "suspended") {
+		t.Fatalf("Expected the restart to be refused while db is suspended, but got %v", err)
+	}
+	if worker.unloads != 0 {
+		t.Fatalf("Expected a refused restart to leave worker loaded, but it was unloaded %v times", worker.unloads)
+	}
+	if err := manager.RestartPlugin("db", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if db.loads != 2 || db.unloads != 1 {
+		t.Errorf("Expected db to be loaded twice and unloaded once, but got %v and %v", db.loads, db.unloads)
+	}
+	if manager.isSuspended("db") {
+		t.Error("Expected the restart to resume db")
+	}
+	if err := manager.RestartPlugin("worker", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if worker.loads != 2 || worker.unloads != 1 {
+		t.Errorf("Expected worker to be loaded twice and unloaded once, but got %v and %v", worker.loads, worker.unloads)
+	}
+	if s := manager.LastShutdown(); s.Duration != 0 || len(s.Unloaded) != 0 {
+		t.Errorf("Expected a restart not to be reported as shutdown, but got %+v", s)
+	}
+}
+
+type quiescingPlugin struct {
+	MockPlugin
+	quiesced, resumed int32
+}
+
+func (q *quiescingPlugin) Quiesce(context.Context) error {
+	atomic.AddInt32(&q.quiesced, 1)
+	return nil
+}
+
+func (q *quiescingPlugin) Resume(context.Context) error {
+	atomic.AddInt32(&q.resumed, 1)
+	return nil
+}
+
+func TestSuspendPlugin(t *testing.T) {
+	db := &quiescingPlugin{MockPlugin: MockPlugin{name: "db"}}
+	worker := &MockPlugin{name: "worker", depends: []string{"db"}}
+	manager := newTestApp(t, "", db, worker)
+	manager.LoadPlugins(Lynx().GlobalConfig())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = manager.SuspendPlugin(context.Background(), "db")
+		}()
+	}
+	wg.Wait()
+	if db.quiesced != 1 {
+		t.Errorf("Expected concurrent suspends to quiesce db once, but it was quiesced %v times", db.quiesced)
+	}
+
+	snapshot := manager.CheckHealth(context.Background())
+	if snapshot.Status != plugin.HealthHealthy {
+		t.Errorf("Expected a suspension not to lower the status, but got %v", snapshot.Status)
+	}
+	for _, p := range snapshot.Plugins {
+		want := plugin.HealthHealthy
+		if p.Name == "db" {
+			want = HealthSuspended
+		}
+		if p.Effective != want {
+			t.Errorf("Expected %v to be %v, but got %v", p.Name, want, p.Effective)
+		}
+	}
+	if _, ok := manager.HealthReport(context.Background())["db"]; ok {
+		t.Error("Expected the health report to skip the suspended db")
+	}
+
+	// Quiescing and resuming the application leaves the suspended db paused
+	if err := manager.Quiesce(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := manager.Resume(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if db.quiesced != 1 || db.resumed != 0 {
+		t.Errorf("Expected the suspended db to be left alone, but it was quiesced %v and resumed %v times", db.quiesced, db.resumed)
+	}
+	if err := manager.ResumePlugin(context.Background(), "db"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if db.resumed != 1 {
+		t.Errorf("Expected ResumePlugin to resume db, but it was resumed %v times", db.resumed)
+	}
+}
+
+type hangingPlugin struct {
+	MockPlugin
+	release  chan struct{}
+	unloaded chan struct{}
+}
+
+func (h *hangingPlugin) Load(config.Value) (plugin.Plugin, error) {
+	Lynx().PlugManager().Heartbeat(h.name, "connecting to the broker")
+	<-h.release
+	return h, nil
+}
+
+func (h *hangingPlugin) Unload() error {
+	close(h.unloaded)
+	return nil
+}
+
+func TestLoadWatchdog(t *testing.T) {
+	broker := &hangingPlugin{MockPlugin: MockPlugin{name: "broker"}, release: make(chan struct{}), unloaded: make(chan struct{})}
+	manager := newTestApp(t, `"plugins": {"load_inactivity_timeout": "0.05s"}`, broker)
+	sorted, err := manager.TopologicalSort(manager.pluginList)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager.progress.begin(sorted)()
+
+	err = manager.loadPlugin(broker, Lynx().GlobalConfig())
+	if err == nil || !strings.Contains(err.Error(), "hung") {
+		t.Fatalf("Expected the watchdog to report broker as hung, but got %v", err)
+	}
+	if p := manager.StartupProgress().Plugins[0]; p.Heartbeat != "connecting to the broker" {
+		t.Errorf("Expected the last heartbeat in the startup progress, but got %q", p.Heartbeat)
+	}
+	if err := manager.RestartPlugin("broker", false); err == nil {
+		t.Error("Expected a restart to be refused while the abandoned load is running")
+	}
+
+	// The abandoned load returning late is unloaded again
+	close(broker.release)
+	select {
+	case <-broker.unloaded:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the abandoned load to be unloaded")
+	}
+}
+
+type slowUnloadPlugin struct {
+	MockPlugin
+	release chan struct{}
+}
+
+func (s *slowUnloadPlugin) Unload() error {
+	<-s.release
+	return nil
+}
+
+func TestUnloadBudget(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := &MockPlugin{name: "server", depends: []string{"worker"}, phase: plugin.PhaseIngress}
+	worker := &slowUnloadPlugin{MockPlugin: MockPlugin{name: "worker", depends: []string{"db"}}, release: release}
+	consumer := &MockPlugin{name: "consumer", depends: []string{"db"}}
+	db := &MockPlugin{name: "db", phase: plugin.PhaseStores}
+	manager := newTestApp(t, "", server, worker, consumer, db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := manager.UnloadPluginsContext(ctx)
+	if err == nil || !strings.Contains(err.Error(), "unfinished [worker db]") {
+		t.Fatalf("Expected worker and db to be reported unfinished, but got %v", err)
+	}
+	summary := manager.LastShutdown()
+	// consumer unloads in parallel with the hanging worker
+	if strings.Join(summary.Unloaded, ",") != "server,consumer" {
+		t.Errorf("Expected server and consumer to be unloaded, but got %v", summary.Unloaded)
+	}
+	if _, ok := summary.Phases[plugin.PhaseWorkers]; !ok {
+		t.Errorf("Expected the time of the workers phase in %v", summary.Phases)
+	}
+}
+
+type configurablePlugin struct {
+	MockPlugin
+	addrs []string
+}
+
+func (c *configurablePlugin) Configure(v config.Value) error {
+	var conf struct {
+		Addr string `json:"addr"`
+	}
+	if err := v.Scan(&conf); err != nil {
+		return err
+	}
+	if conf.Addr == "invalid" {
+		return errors.New("invalid address")
+	}
+	c.addrs = append(c.addrs, conf.Addr)
+	return nil
+}
+
+func TestReloadRollback(t *testing.T) {
+	db := &configurablePlugin{MockPlugin: MockPlugin{name: "db", confPrefix: "lynx.db"}}
+	cache := &configurablePlugin{MockPlugin: MockPlugin{name: "cache", confPrefix: "lynx.cache", depends: []string{"db"}}}
+	manager := newTestApp(t, `"db": {"addr": "db-1"}, "cache": {"addr": "cache-1"}`, db, cache)
+	manager.LoadPlugins(Lynx().GlobalConfig())
+	prev := Lynx().GlobalConfig()
+	var changes []ConfigChange
+	manager.OnConfigChange(func(change ConfigChange) {
+		changes = append(changes, change)
+	})
+
+	next := config.New(config.WithSource(NewStaticSource("next", []byte(
+		`{"lynx": {"application": {"name": "test", "version": "v1"}, "db": {"addr": "db-2"}, "cache": {"addr": "invalid"}}}`), "json")))
+	if err := next.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer next.Close()
+
+	err := manager.ReloadConfig(next)
+	var reloadErr *ReloadError
+	if !errors.As(err, &reloadErr) || reloadErr.Plugin != "cache" || len(reloadErr.RollbackErrors) != 0 {
+		t.Fatalf("Expected cache to reject the configuration, but got %v", err)
+	}
+	// db was reconfigured first and rolled back to its previous address
+	if strings.Join(db.addrs, ",") != "db-2,db-1" {
+		t.Errorf("Expected db to be rolled back, but it was configured with %v", db.addrs)
+	}
+	if len(cache.addrs) != 0 {
+		t.Errorf("Expected cache to keep its configuration, but it was configured with %v", cache.addrs)
+	}
+	if Lynx().GlobalConfig() != prev {
+		t.Error("Expected the previous configuration to stay the global one")
+	}
+	if len(changes) != 1 || changes[0].Plugin != "cache" || !changes[0].ReloadFailed ||
+		!strings.Contains(changes[0].Error, "invalid address") {
+		t.Errorf("Expected a failed reload of cache to be reported, but got %+v", changes)
+	}
+}
+
+type drainingPlugin struct {
+	MockPlugin
+	drains   int32
+	unloaded int32
+	// drainedFirst is set when Drain returned before the plugin was unloaded
+	drainedFirst bool
+}
+
+func (d *drainingPlugin) Drain(ctx context.Context) error {
+	atomic.AddInt32(&d.drains, 1)
+	<-ctx.Done()
+	d.drainedFirst = atomic.LoadInt32(&d.unloaded) == 0
+	return ctx.Err()
+}
+
+func (d *drainingPlugin) Unload() error {
+	atomic.AddInt32(&d.unloaded, 1)
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	server := &drainingPlugin{MockPlugin: MockPlugin{name: "server", phase: plugin.PhaseIngress}}
+	manager := newTestApp(t, `"shutdown": {"drain_timeout": "0.05s"}`, server, &MockPlugin{name: "db"})
+
+	st := time.Now()
+	manager.Drain(context.Background())
+	if waited := time.Since(st); waited < 50*time.Millisecond || waited > time.Second {
+		t.Errorf("Expected draining to be bounded by the drain timeout, but it took %v", waited)
+	}
+	// The drain from the BeforeStop hook isn't repeated when the plugins are unloaded
+	if err := manager.UnloadPluginsContext(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if drains := atomic.LoadInt32(&server.drains); drains != 1 {
+		t.Errorf("Expected the server to be drained once, but it was drained %v times", drains)
+	}
+	if !server.drainedFirst || atomic.LoadInt32(&server.unloaded) != 1 {
+		t.Error("Expected the server to be drained before it was unloaded")
+	}
+	if _, ok := manager.LastShutdown().Phases["drain"]; !ok {
+		t.Errorf("Expected the time of draining in %v", manager.LastShutdown().Phases)
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// defaultPreWarmTimeout is used when lynx.plugins.prewarm_timeout is not configured
+const defaultPreWarmTimeout = 30 * time.Second
+
+// preWarm establishes the configured number of connections of a freshly loaded plugin, the plugin stays in the
+// warming state and therefore not ready until it is done. A failed pre-warm only costs the first requests their
+// connection setup, so it is logged rather than failing the startup.
+func (m *DefaultLynxPluginManager) preWarm(p plugin.Plugin) {
+	w, ok := p.(plugin.PreWarmer)
+	if !ok {
+		return
+	}
+	conf := Lynx().pluginsConf()
+	n := int(conf.GetPrewarm()[p.Name()])
+	if n <= 0 {
+		return
+	}
+	timeout := defaultPreWarmTimeout
+	if t := conf.GetPrewarmTimeout(); t != nil {
+		timeout = t.AsDuration()
+	}
+
+	m.progress.update(p.Name(), PluginWarming, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	if err := w.PreWarm(ctx, n); err != nil {
+		Lynx().PluginHelper(p.Name()).Warnf("Pre-warming %v plugin failed, continuing with a cold pool: %v", p.Name(), err)
+		return
+	}
+	Lynx().PluginHelper(p.Name()).Infof("Pre-warmed %v connections of %v plugin in %v", n, p.Name(), time.Since(start))
+}
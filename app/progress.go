@@ -0,0 +1,197 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Plugin startup states reported by StartupProgress
+const (
+	PluginPending = "pending"
+	PluginLoading = "loading"
+	PluginWarming = "warming"
+	PluginLoaded  = "loaded"
+	PluginFailed  = "failed"
+	PluginSkipped = "skipped"
+)
+
+// defaultProgressLogInterval is used when lynx.plugins.progress_log_interval is not configured
+const defaultProgressLogInterval = 5 * time.Second
+
+// PluginProgress is the startup state of a single plugin
+type PluginProgress struct {
+	Name    string        `json:"name"`
+	Level   int           `json:"level"`
+	Status  string        `json:"status"`
+	Elapsed time.Duration `json:"elapsed"`
+	Error   string        `json:"error,omitempty"`
+	// Heartbeat is the last progress the plugin reported through Heartbeat while loading
+	Heartbeat   string    `json:"heartbeat,omitempty"`
+	HeartbeatAt time.Time `json:"heartbeat_at,omitempty"`
+}
+
+// StartupProgress is a snapshot of the plugin startup
+type StartupProgress struct {
+	StartedAt time.Time        `json:"started_at"`
+	Elapsed   time.Duration    `json:"elapsed"`
+	Loading   bool             `json:"loading"`
+	Plugins   []PluginProgress `json:"plugins"`
+}
+
+// String renders the progress as a single log line, e.g. "level 2 [db:loaded redis:loading http:pending] 12s"
+func (p StartupProgress) String() string {
+	level := 0
+	states := make([]string, 0, len(p.Plugins))
+	for _, pp := range p.Plugins {
+		if pp.Status == PluginLoading || pp.Status == PluginWarming || pp.Status == PluginFailed {
+			level = pp.Level
+		}
+		states = append(states, pp.Name+":"+pp.Status)
+	}
+	return fmt.Sprintf("level %v [%v] %v", level, strings.Join(states, " "), p.Elapsed.Truncate(time.Millisecond))
+}
+
+// startupTracker records the startup state of plugins as the manager loads them
+type startupTracker struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	active    int
+	order     []string
+	plugins   map[string]*PluginProgress
+	started   map[string]time.Time
+	stopLog   chan struct{}
+}
+
+func newStartupTracker() *startupTracker {
+	return &startupTracker{
+		plugins: make(map[string]*PluginProgress),
+		started: make(map[string]time.Time),
+	}
+}
+
+// begin marks the plugins as pending and starts the periodic progress log when no load is running yet.
+// Loads may nest, e.g. a control plane plugin loading the remaining plugins, the returned func ends this load.
+func (t *startupTracker) begin(plugins []PluginWithLevel) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == 0 && t.startedAt.IsZero() {
+		t.startedAt = time.Now()
+	}
+	for _, p := range plugins {
+		if _, ok := t.plugins[p.Name()]; !ok {
+			t.order = append(t.order, p.Name())
+		}
+		t.plugins[p.Name()] = &PluginProgress{Name: p.Name(), Level: p.level, Status: PluginPending}
+	}
+	t.active++
+	if t.active == 1 {
+		// The goroutine doesn't read the global application, which a new application may replace meanwhile
+		interval := Lynx().pluginsConf().GetProgressLogInterval().AsDuration()
+		if interval <= 0 {
+			interval = defaultProgressLogInterval
+		}
+		logger := log.NewHelper(log.GetLogger())
+		if Lynx() != nil && Lynx().Helper() != nil {
+			logger = Lynx().Helper()
+		}
+		t.stopLog = make(chan struct{})
+		go t.logPeriodically(t.stopLog, interval, logger)
+	}
+	return t.end
+}
+
+func (t *startupTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 {
+		close(t.stopLog)
+	}
+}
+
+func (t *startupTracker) update(name string, status string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.plugins[name]
+	if !ok {
+		return
+	}
+	p.Status = status
+	switch status {
+	case PluginLoading:
+		t.started[name] = time.Now()
+	case PluginLoaded, PluginFailed:
+		p.Elapsed = time.Since(t.started[name])
+	}
+	if err != nil {
+		p.Error = err.Error()
+	} else if status == PluginLoading {
+		// A plugin loaded again, e.g. by RestartPlugin, starts without the error of the previous attempt
+		p.Error = ""
+	}
+	if status == PluginLoading {
+		p.Heartbeat, p.HeartbeatAt = "", time.Time{}
+	}
+}
+
+// beat records the last progress reported by a loading plugin
+func (t *startupTracker) beat(name string, progress string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.plugins[name]; ok {
+		p.Heartbeat, p.HeartbeatAt = progress, time.Now()
+	}
+}
+
+func (t *startupTracker) snapshot() StartupProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := StartupProgress{
+		StartedAt: t.startedAt,
+		Loading:   t.active > 0,
+		Plugins:   make([]PluginProgress, 0, len(t.order)),
+	}
+	if !t.startedAt.IsZero() {
+		s.Elapsed = time.Since(t.startedAt)
+	}
+	for _, name := range t.order {
+		p := *t.plugins[name]
+		if p.Status == PluginLoading {
+			p.Elapsed = time.Since(t.started[name])
+		}
+		s.Plugins = append(s.Plugins, p)
+	}
+	return s
+}
+
+func (t *startupTracker) logPeriodically(stop chan struct{}, interval time.Duration, logger *log.Helper) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			logger.Infof("Lynx startup in progress: %v", t.snapshot())
+		}
+	}
+}
+
+// StartupProgress returns the current startup state of all plugins
+func (m *DefaultLynxPluginManager) StartupProgress() StartupProgress {
+	return m.progress.snapshot()
+}
+
+// StartupProgressHandler serves the startup progress as JSON, mount it on a server to watch slow deployments,
+// e.g. http.GetServer().Handle("/startup", app.StartupProgressHandler())
+func StartupProgressHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Lynx().PlugManager().StartupProgress())
+	})
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// Quiesce stops the background work of all plugins while the application keeps serving, see plugin.Quiescer
+func Quiesce(ctx context.Context) error {
+	return Lynx().PlugManager().Quiesce(ctx)
+}
+
+// Resume restarts the background work stopped by Quiesce
+func Resume(ctx context.Context) error {
+	return Lynx().PlugManager().Resume(ctx)
+}
+
+// Quiesce pauses the loaded plugins implementing plugin.Quiescer, dependents before the plugins they depend on.
+// Every plugin is asked even when another one fails, the failures are reported together. Plugins suspended with
+// SuspendPlugin are already paused and left alone.
+func (m *DefaultLynxPluginManager) Quiesce(ctx context.Context) error {
+	m.suspendMu.Lock()
+	defer m.suspendMu.Unlock()
+	sorted := m.reverseTopological(m.activePlugins())
+	var failed []string
+	for _, p := range sorted {
+		q, ok := p.Plugin.(plugin.Quiescer)
+		if !ok {
+			continue
+		}
+		if err := q.Quiesce(ctx); err != nil {
+			failed = append(failed, p.Name())
+			Lynx().PluginHelper(p.Name()).Errorf("Exception in quiescing %v plugin : %v", p.Name(), err)
+			continue
+		}
+		Lynx().PluginHelper(p.Name()).Infof("Plugin %v quiesced", p.Name())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("quiesce plugins: failed %v", failed)
+	}
+	return nil
+}
+
+// Resume resumes the loaded plugins implementing plugin.Quiescer, dependencies before their dependents. Plugins
+// suspended with SuspendPlugin stay paused until ResumePlugin.
+func (m *DefaultLynxPluginManager) Resume(ctx context.Context) error {
+	m.suspendMu.Lock()
+	defer m.suspendMu.Unlock()
+	sorted := m.reverseTopological(m.activePlugins())
+	var failed []string
+	for i := len(sorted) - 1; i >= 0; i-- {
+		q, ok := sorted[i].Plugin.(plugin.Quiescer)
+		if !ok {
+			continue
+		}
+		if err := q.Resume(ctx); err != nil {
+			failed = append(failed, sorted[i].Name())
+			Lynx().PluginHelper(sorted[i].Name()).Errorf("Exception in resuming %v plugin : %v", sorted[i].Name(), err)
+			continue
+		}
+		Lynx().PluginHelper(sorted[i].Name()).Infof("Plugin %v resumed", sorted[i].Name())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("resume plugins: failed %v", failed)
+	}
+	return nil
+}
+
+// activePlugins returns the loaded plugins that aren't suspended
+func (m *DefaultLynxPluginManager) activePlugins() []plugin.Plugin {
+	var active []plugin.Plugin
+	for _, p := range m.loadedPlugins() {
+		if !m.isSuspended(p.Name()) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-lynx/lynx/plugin"
+	"net/http"
+	"sync"
+)
+
+// DependencyReadiness is the result of a single readiness dependency check
+type DependencyReadiness struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// PluginReadiness is the readiness of a single plugin
+type PluginReadiness struct {
+	Name         string                `json:"name"`
+	Ready        bool                  `json:"ready"`
+	Status       string                `json:"status"`
+	Dependencies []DependencyReadiness `json:"dependencies,omitempty"`
+}
+
+// ReadinessReport aggregates the readiness of all plugins, the application is ready when every plugin is
+type ReadinessReport struct {
+	Ready   bool              `json:"ready"`
+	Plugins []PluginReadiness `json:"plugins"`
+}
+
+// CheckReadiness reports whether every plugin is loaded and all declared readiness dependencies pass,
+// dependency checks run concurrently and are bounded by ctx. Dependencies of plugins that are not loaded are
+// not checked, their clients don't exist yet or anymore.
+func (m *DefaultLynxPluginManager) CheckReadiness(ctx context.Context) ReadinessReport {
+	states := make(map[string]string)
+	for _, p := range m.progress.snapshot().Plugins {
+		states[p.Name] = p.Status
+	}
+
+	report := ReadinessReport{Ready: true, Plugins: make([]PluginReadiness, len(m.pluginList))}
+	var wg sync.WaitGroup
+	for i, p := range m.pluginList {
+		status, ok := states[p.Name()]
+		if !ok {
+			status = PluginPending
+		}
+		report.Plugins[i] = PluginReadiness{Name: p.Name(), Status: status, Ready: status == PluginLoaded}
+
+		provider, ok := p.(plugin.ReadinessProvider)
+		if !ok || status != PluginLoaded {
+			continue
+		}
+		deps := provider.ReadinessDependencies()
+		report.Plugins[i].Dependencies = make([]DependencyReadiness, len(deps))
+		for j, dep := range deps {
+			wg.Add(1)
+			go func(result *DependencyReadiness, dep plugin.ReadinessDependency) {
+				defer wg.Done()
+				result.Name = dep.Name
+				if err := dep.Check(ctx); err != nil {
+					result.Error = err.Error()
+					return
+				}
+				result.Ready = true
+			}(&report.Plugins[i].Dependencies[j], dep)
+		}
+	}
+	wg.Wait()
+
+	for i := range report.Plugins {
+		for _, dep := range report.Plugins[i].Dependencies {
+			if !dep.Ready {
+				report.Plugins[i].Ready = false
+			}
+		}
+		if !report.Plugins[i].Ready {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// ReadinessHandler serves the readiness report as JSON, answering 503 while the application is not ready,
+// e.g. http.GetServer().Handle("/ready", app.ReadinessHandler())
+func ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Lynx().PlugManager().CheckReadiness(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
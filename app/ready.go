@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// defaultReadyTimeout is used when lynx.plugins.ready_timeout is not configured
+const defaultReadyTimeout = 30 * time.Second
+
+// waitDependencies waits until the loaded dependencies of p implementing plugin.Ready are ready, so p doesn't
+// connect to a dependency that finished Load but can't serve yet
+func (m *DefaultLynxPluginManager) waitDependencies(p plugin.Plugin) error {
+	timeout := Lynx().pluginsConf().GetReadyTimeout().AsDuration()
+	if timeout <= 0 {
+		timeout = defaultReadyTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	deps := append(append([]string(nil), dependsOn(p)...), optionalDependsOn(p)...)
+	for _, name := range deps {
+		if m.isSuspended(name) {
+			return fmt.Errorf("dependency %v of %v plugin is suspended", name, p.Name())
+		}
+		r, ok := m.pluginMap[name].(plugin.Ready)
+		if !ok {
+			continue
+		}
+		if err := r.WaitReady(ctx); err != nil {
+			return fmt.Errorf("dependency %v of %v plugin is not ready: %w", name, p.Name(), err)
+		}
+	}
+	return nil
+}
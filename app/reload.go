@@ -0,0 +1,113 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// ReloadError is returned by ReloadConfig when a plugin rejects the new configuration
+type ReloadError struct {
+	// Plugin is the name of the plugin that rejected the configuration
+	Plugin string
+	Err    error
+	// RollbackErrors holds the plugins that could not be restored to the previous configuration
+	RollbackErrors map[string]error
+}
+
+func (e *ReloadError) Error() string {
+	if len(e.RollbackErrors) > 0 {
+		return fmt.Sprintf("plugin %v rejected the configuration: %v, rollback failed: %v", e.Plugin, e.Err, e.RollbackErrors)
+	}
+	return fmt.Sprintf("plugin %v rejected the configuration: %v", e.Plugin, e.Err)
+}
+
+func (e *ReloadError) Unwrap() error {
+	return e.Err
+}
+
+// ReloadConfig applies next to all loaded Configurable plugins, dependencies first. When a plugin rejects it,
+// the plugins already reconfigured are rolled back to the current configuration, the application keeps running
+// on it and the OnConfigChange hooks are called with a ReloadFailed change. Only when every plugin accepts does
+// next become the global configuration.
+func (m *DefaultLynxPluginManager) ReloadConfig(next config.Config) error {
+	err := m.reloadConfig(next)
+	var reloadErr *ReloadError
+	if errors.As(err, &reloadErr) {
+		m.notifyConfigChange(ConfigChange{Plugin: reloadErr.Plugin, Error: reloadErr.Error(), ReloadFailed: true})
+	}
+	return err
+}
+
+func (m *DefaultLynxPluginManager) reloadConfig(next config.Config) error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	if err := CheckConfig(next); err != nil {
+		Lynx().Helper().Errorf("Config reload failed, keeping the previous configuration: %v", err)
+		return err
+	}
+
+	prev := Lynx().GlobalConfig()
+	sorted := m.reverseTopological(m.loadedPlugins())
+	var applied []plugin.Plugin
+	for i := len(sorted) - 1; i >= 0; i-- {
+		p := sorted[i].Plugin
+		c, ok := p.(plugin.Configurable)
+		if !ok {
+			continue
+		}
+		err := configureWith(c, p, next)
+		if err != nil {
+			reloadErr := &ReloadError{Plugin: p.Name(), Err: err}
+			// Restore dependents before the plugins they depend on
+			for j := len(applied) - 1; j >= 0; j-- {
+				rb := applied[j]
+				if rbErr := configureWith(rb.(plugin.Configurable), rb, prev); rbErr != nil {
+					if reloadErr.RollbackErrors == nil {
+						reloadErr.RollbackErrors = make(map[string]error)
+					}
+					reloadErr.RollbackErrors[rb.Name()] = rbErr
+				}
+			}
+			Lynx().Helper().Errorf("Config reload failed, keeping the previous configuration: %v", reloadErr)
+			return reloadErr
+		}
+		applied = append(applied, p)
+	}
+
+	Lynx().setGlobalConfig(next)
+	reconfigureBreakers(next)
+	m.watchConfig()
+	names := make([]string, len(applied))
+	for i, p := range applied {
+		names[i] = p.Name()
+	}
+	Lynx().Helper().Infof("Config reloaded, reconfigured plugins: %v", names)
+	return nil
+}
+
+// loadedPlugins returns the plugins that finished loading, in the order they were added
+func (m *DefaultLynxPluginManager) loadedPlugins() []plugin.Plugin {
+	states := make(map[string]string)
+	for _, p := range m.progress.snapshot().Plugins {
+		states[p.Name] = p.Status
+	}
+	var loaded []plugin.Plugin
+	for _, p := range m.pluginList {
+		if states[p.Name()] == PluginLoaded {
+			loaded = append(loaded, p)
+		}
+	}
+	return loaded
+}
+
+// configureWith passes the migrated configuration of p in c to its Configure
+func configureWith(c plugin.Configurable, p plugin.Plugin, conf config.Config) error {
+	value, err := pluginConfig(p, conf)
+	if err != nil {
+		return err
+	}
+	return c.Configure(value)
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// inheritedListenersEnv names the listeners passed to a re-spawned process, the listener at index i is
+// inherited as file descriptor 3+i
+const inheritedListenersEnv = "LYNX_INHERITED_LISTENERS"
+
+var (
+	listenersMu sync.Mutex
+	// listeners holds the listeners that are handed over on a graceful restart, by name
+	listeners = make(map[string]net.Listener)
+	// inherited holds the listeners passed by the parent process that haven't been claimed yet
+	inherited map[string]net.Listener
+	// handedOver is set once Restart started the process taking over the listeners
+	handedOver atomic.Bool
+)
+
+// Listen returns the listener of a server plugin. After a graceful restart it returns the listener inherited from
+// the previous process, so connections keep being accepted while the previous process drains. Server plugins
+// pass it to their server, e.g. http.Listener(lis). The network defaults to tcp and the address to ":0".
+func Listen(name, network, address string) (net.Listener, error) {
+	if network == "" {
+		network = "tcp"
+	}
+	if address == "" {
+		address = ":0"
+	}
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	if inherited == nil {
+		inherited = inheritListeners()
+	}
+	lis, ok := inherited[name]
+	if ok {
+		delete(inherited, name)
+	} else {
+		var err error
+		if lis, err = net.Listen(network, address); err != nil {
+			return nil, err
+		}
+	}
+	listeners[name] = lis
+	return lis, nil
+}
+
+// inheritListeners picks up the listeners passed by the parent process
+func inheritListeners() map[string]net.Listener {
+	result := make(map[string]net.Listener)
+	names := os.Getenv(inheritedListenersEnv)
+	if names == "" {
+		return result
+	}
+	for i, name := range strings.Split(names, ",") {
+		f := os.NewFile(uintptr(3+i), name)
+		lis, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			if Lynx() != nil && Lynx().Helper() != nil {
+				Lynx().Helper().Warnf("Failed to inherit listener %v: %v", name, err)
+			}
+			continue
+		}
+		result[name] = lis
+	}
+	return result
+}
+
+// Restart starts a new process from the current binary and hands it the listeners obtained through Listen. The
+// current process keeps serving until it is stopped, the caller is expected to shut it down gracefully so
+// in-flight requests drain while the new process accepts new connections.
+func Restart() (int, error) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+
+	var names []string
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for name, lis := range listeners {
+		filer, ok := lis.(interface{ File() (*os.File, error) })
+		if !ok {
+			return 0, fmt.Errorf("listener %v of type %T can't be handed over", name, lis)
+		}
+		f, err := filer.File()
+		if err != nil {
+			return 0, err
+		}
+		names = append(names, name)
+		files = append(files, f)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(), inheritedListenersEnv+"="+strings.Join(names, ","))
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	handedOver.Store(true)
+	return cmd.Process.Pid, nil
+}
+
+// HandedOver reports whether Restart started a process that took over the listeners. The new process serves the
+// same endpoints under the same instance ID and registers them itself, so the current process must not deregister
+// the instance when it stops, that would remove the registration of the new process.
+func HandedOver() bool {
+	return handedOver.Load()
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+// RestartSignals trigger a graceful restart, see Restart
+var RestartSignals = []os.Signal{syscall.SIGUSR2}
@@ -0,0 +1,6 @@
+package app
+
+import "os"
+
+// RestartSignals trigger a graceful restart, handing listeners over is not supported on windows
+var RestartSignals []os.Signal
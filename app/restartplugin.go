@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// RestartPlugin unloads and loads a single plugin again, e.g. to recover a plugin that failed, leaving the other
+// plugins untouched. It fails when loaded plugins depend on it, since they hold on to what the plugin provides,
+// unless force is set. Like at startup, the plugin is only loaded again once its dependencies are ready, and the
+// restart is refused while one of them is suspended. Plugins serving traffic can't be restarted, their servers are
+// owned by the running app.
+func (m *DefaultLynxPluginManager) RestartPlugin(name string, force bool) error {
+	p, ok := m.pluginMap[name]
+	if !ok {
+		return fmt.Errorf("unknown plugin %v", name)
+	}
+	if _, serves := p.(plugin.ServerProvider); serves {
+		return fmt.Errorf("plugin %v serves traffic and can't be restarted", name)
+	}
+	m.mu.Lock()
+	abandoned := m.abandoned[name]
+	m.mu.Unlock()
+	if abandoned {
+		return fmt.Errorf("plugin %v is still hung in a previous load", name)
+	}
+	if !force {
+		var dependents []string
+		for _, other := range m.loadedPlugins() {
+			for _, dep := range dependsOn(other) {
+				if dep == name {
+					dependents = append(dependents, other.Name())
+				}
+			}
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf("plugin %v can't be restarted while %v depend on it", name, dependents)
+		}
+	}
+
+	// Checked before unloading, a restart that can't load the plugin again leaves it running
+	if err := m.waitDependencies(p); err != nil {
+		return fmt.Errorf("restart %v plugin: %w", name, err)
+	}
+
+	Lynx().PluginHelper(name).Infof("Restarting %v plugin", name)
+	// A restart is not a shutdown, the summary of the last shutdown stays untouched
+	ctx, cancel := shutdownContext()
+	summary := m.unloadContext(ctx, []unloadBatch{{phase: shutdownPhase(p), plugins: []plugin.Plugin{p}}})
+	cancel()
+	if len(summary.Unfinished) > 0 {
+		// The plugin may still be releasing its resources, loading it again could conflict with them
+		return fmt.Errorf("restart %v plugin: %w", name, summary.err())
+	}
+	// The restarted plugin starts out of suspension
+	m.suspendMu.Lock()
+	m.mu.Lock()
+	delete(m.suspended, name)
+	m.mu.Unlock()
+	m.suspendMu.Unlock()
+
+	m.progress.update(name, PluginLoading, nil)
+	if err := m.loadPlugin(p, Lynx().GlobalConfig()); err != nil {
+		m.progress.update(name, PluginFailed, err)
+		Lynx().PluginHelper(name).Errorf("Exception in restarting %v plugin : %v", name, err)
+		return err
+	}
+	m.preWarm(p)
+	m.progress.update(name, PluginLoaded, nil)
+	m.watchPlugin(p)
+	Lynx().PluginHelper(name).Infof("Plugin %v restarted", name)
+	return nil
+}
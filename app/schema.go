@@ -0,0 +1,105 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// jsonSchema is the subset of JSON schema plugins describe their configuration with
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+}
+
+// validateSchema checks a decoded JSON value against a schema, returning one error per violation
+func validateSchema(schema []byte, path string, v interface{}) ([]error, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema of %v: %w", path, err)
+	}
+	return s.validate(path, v), nil
+}
+
+func (s *jsonSchema) validate(path string, v interface{}) []error {
+	if s.Type != "" && !hasSchemaType(s.Type, v) {
+		return []error{fmt.Errorf("%v: expected %v, got %v", path, s.Type, jsonType(v))}
+	}
+	var errs []error
+	if len(s.Enum) > 0 {
+		allowed := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			errs = append(errs, fmt.Errorf("%v: %v is not one of %v", path, v, s.Enum))
+		}
+	}
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		for _, r := range s.Required {
+			if _, ok := vt[r]; !ok {
+				errs = append(errs, fmt.Errorf("%v.%v: required field is missing", path, r))
+			}
+		}
+		keys := make([]string, 0, len(vt))
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := s.Properties[k]; ok {
+				errs = append(errs, sub.validate(path+"."+k, vt[k])...)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, fmt.Errorf("%v.%v: unknown field", path, k))
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range vt {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%v[%v]", path, i), item)...)
+			}
+		}
+	}
+	return errs
+}
+
+func hasSchemaType(t string, v interface{}) bool {
+	switch t {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return jsonType(v) == t
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
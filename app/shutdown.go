@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// defaultShutdownTimeout is used when lynx.shutdown.timeout is not configured
+const defaultShutdownTimeout = 30 * time.Second
+
+// shutdownPhase resolves the shutdown phase of a plugin, the configuration takes precedence over the phase
+// declared by the plugin itself. An unknown phase falls back to the workers phase, it is reported once when the
+// plugin is loaded, see checkShutdownPhases.
+func shutdownPhase(p plugin.Plugin) string {
+	phase := declaredShutdownPhase(p)
+	if !knownShutdownPhase(phase) {
+		return plugin.PhaseWorkers
+	}
+	return phase
+}
+
+// declaredShutdownPhase returns the phase configured or declared for a plugin, without checking it
+func declaredShutdownPhase(p plugin.Plugin) string {
+	phase := plugin.PhaseWorkers
+	if s, ok := p.(plugin.ShutdownPhaser); ok && s.ShutdownPhase() != "" {
+		phase = s.ShutdownPhase()
+	}
+	if Lynx() != nil && Lynx().bootConf != nil {
+		if c, ok := Lynx().bootConf.GetLynx().GetShutdown().GetPhases()[p.Name()]; ok {
+			phase = c
+		}
+	}
+	return phase
+}
+
+func knownShutdownPhase(phase string) bool {
+	for _, known := range plugin.ShutdownPhases {
+		if phase == known {
+			return true
+		}
+	}
+	return false
+}
+
+// checkShutdownPhases warns about plugins with an unknown shutdown phase before they are loaded
+func checkShutdownPhases(plugins []PluginWithLevel) {
+	for _, p := range plugins {
+		if phase := declaredShutdownPhase(p.Plugin); !knownShutdownPhase(phase) {
+			Lynx().PluginHelper(p.Name()).Warnf("Unknown shutdown phase %v of plugin %v, using %v", phase, p.Name(), plugin.PhaseWorkers)
+		}
+	}
+}
+
+// unloadOrder returns the plugins in the order they should be unloaded: phase by phase, and within a phase
+// dependents before the plugins they depend on
+func (m *DefaultLynxPluginManager) unloadOrder(plugins []plugin.Plugin) []plugin.Plugin {
+	ordered := make([]plugin.Plugin, 0, len(plugins))
+	for _, batch := range m.unloadBatches(plugins) {
+		ordered = append(ordered, batch.plugins...)
+	}
+	return ordered
+}
+
+// unloadBatch holds plugins of one phase on the same dependency level, they don't depend on each other and can be
+// unloaded in parallel
+type unloadBatch struct {
+	phase   string
+	plugins []plugin.Plugin
+}
+
+// unloadBatches groups the plugins into batches that are unloaded one after another
+func (m *DefaultLynxPluginManager) unloadBatches(plugins []plugin.Plugin) []unloadBatch {
+	sorted := m.reverseTopological(plugins)
+	var batches []unloadBatch
+	for _, phase := range plugin.ShutdownPhases {
+		level := -1
+		for _, p := range sorted {
+			if shutdownPhase(p.Plugin) != phase {
+				continue
+			}
+			if p.level != level {
+				batches = append(batches, unloadBatch{phase: phase})
+				level = p.level
+			}
+			batches[len(batches)-1].plugins = append(batches[len(batches)-1].plugins, p.Plugin)
+		}
+	}
+	return batches
+}
+
+// reverseTopological sorts the plugins so that dependents come before their dependencies. Unloading must never
+// fail because of the dependency graph, so the original order is kept when the graph can't be resolved, with
+// every plugin on its own level.
+func (m *DefaultLynxPluginManager) reverseTopological(plugins []plugin.Plugin) (result []PluginWithLevel) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = sequential(plugins)
+		}
+	}()
+	sorted, err := m.TopologicalSort(plugins)
+	if err != nil {
+		return sequential(plugins)
+	}
+	result = make([]PluginWithLevel, 0, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		result = append(result, sorted[i])
+	}
+	return result
+}
+
+func sequential(plugins []plugin.Plugin) []PluginWithLevel {
+	result := make([]PluginWithLevel, len(plugins))
+	for i, p := range plugins {
+		result[i] = PluginWithLevel{p, len(plugins) - i}
+	}
+	return result
+}
+
+// unloadBatchesContext unloads the batches like unloadContext, then keeps the summary as the last shutdown and
+// logs it. Unfinished plugins are reported in the returned error together with the unload failures.
+func (m *DefaultLynxPluginManager) unloadBatchesContext(ctx context.Context, batches []unloadBatch) error {
+	summary := m.unloadContext(ctx, batches)
+	m.mu.Lock()
+	if m.drainDuration > 0 {
+		summary.Phases[phaseDrain] = m.drainDuration
+	}
+	m.lastShutdown = summary
+	m.mu.Unlock()
+
+	if len(summary.Unfinished) > 0 {
+		Lynx().Helper().Errorf("Plugins did not finish unloading within the shutdown budget: %v", summary.Unfinished)
+	}
+	Lynx().Helper().Infow("msg", "Lynx shutdown summary", "duration", summary.Duration, "phases", summary.Phases,
+		"unloaded", summary.Unloaded, "failed", summary.Failed, "unfinished", summary.Unfinished)
+	return summary.err()
+}
+
+// unloadContext unloads the batches in order, the plugins of a batch in parallel. Once ctx is done the remaining
+// plugins are abandoned and reported as unfinished.
+func (m *DefaultLynxPluginManager) unloadContext(ctx context.Context, batches []unloadBatch) ShutdownSummary {
+	summary := ShutdownSummary{Phases: make(map[string]time.Duration)}
+	start := time.Now()
+	for i, batch := range batches {
+		if ctx.Err() != nil {
+			for _, rest := range batches[i:] {
+				for _, p := range rest.plugins {
+					summary.Unfinished = append(summary.Unfinished, p.Name())
+				}
+			}
+			break
+		}
+
+		batchStart := time.Now()
+		results := make(chan unloadResult, len(batch.plugins))
+		for _, p := range batch.plugins {
+			go func(p plugin.Plugin) {
+				results <- unloadResult{name: p.Name(), err: p.Unload()}
+			}(p)
+		}
+		pending := make(map[string]bool, len(batch.plugins))
+		for _, p := range batch.plugins {
+			pending[p.Name()] = true
+		}
+	wait:
+		for len(pending) > 0 {
+			select {
+			case r := <-results:
+				delete(pending, r.name)
+				if r.err != nil {
+					summary.Failed = append(summary.Failed, r.name)
+					Lynx().Helper().Errorf("Exception in uninstalling %v plugin : %v", r.name, r.err)
+				} else {
+					summary.Unloaded = append(summary.Unloaded, r.name)
+				}
+			case <-ctx.Done():
+				break wait
+			}
+		}
+		for name := range pending {
+			summary.Unfinished = append(summary.Unfinished, name)
+		}
+		summary.Phases[batch.phase] += time.Since(batchStart)
+	}
+	summary.Duration = time.Since(start)
+	return summary
+}
+
+// ShutdownSummary reports how unloading the plugins went
+type ShutdownSummary struct {
+	// Duration is the total time spent unloading
+	Duration time.Duration `json:"duration"`
+	// Phases holds the time spent draining and per shutdown phase, see plugin.ShutdownPhases
+	Phases map[string]time.Duration `json:"phases"`
+	// Unloaded, Failed and Unfinished name the plugins that unloaded cleanly, returned an error or were abandoned
+	// when the shutdown budget ran out
+	Unloaded   []string `json:"unloaded"`
+	Failed     []string `json:"failed,omitempty"`
+	Unfinished []string `json:"unfinished,omitempty"`
+}
+
+// err reports the plugins that failed or didn't finish unloading
+func (s ShutdownSummary) err() error {
+	if len(s.Failed) > 0 || len(s.Unfinished) > 0 {
+		return fmt.Errorf("unload plugins: failed %v, unfinished %v", s.Failed, s.Unfinished)
+	}
+	return nil
+}
+
+// LastShutdown returns the summary of the last unload, it is empty until plugins were unloaded
+func (m *DefaultLynxPluginManager) LastShutdown() ShutdownSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastShutdown
+}
+
+type unloadResult struct {
+	name string
+	err  error
+}
+
+// ShutdownContext returns a context bounded by lynx.shutdown.timeout, the budget for unloading all plugins
+func ShutdownContext() (context.Context, context.CancelFunc) {
+	return shutdownContext()
+}
+
+// shutdownContext returns a context bounded by the configured shutdown budget
+func shutdownContext() (context.Context, context.CancelFunc) {
+	timeout := defaultShutdownTimeout
+	if Lynx() != nil && Lynx().bootConf != nil {
+		if t := Lynx().bootConf.GetLynx().GetShutdown().GetTimeout(); t != nil {
+			timeout = t.AsDuration()
+		}
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"errors"
+	"github.com/go-kratos/kratos/v2/config"
+	"sync"
+)
+
+// NewStaticSource returns a config source holding data in the given format, e.g. json or yaml, that never
+// changes. Use it for configuration built in code, such as overrides and migrated plugin configuration.
+func NewStaticSource(key string, data []byte, format string) config.Source {
+	return &staticSource{kv: &config.KeyValue{Key: key, Value: data, Format: format}}
+}
+
+// staticSource is a config source that never changes
+type staticSource struct {
+	kv *config.KeyValue
+}
+
+func (s *staticSource) Load() ([]*config.KeyValue, error) {
+	return []*config.KeyValue{s.kv}, nil
+}
+
+func (s *staticSource) Watch() (config.Watcher, error) {
+	return &staticWatcher{stop: make(chan struct{})}, nil
+}
+
+type staticWatcher struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+func (w *staticWatcher) Next() ([]*config.KeyValue, error) {
+	<-w.stop
+	return nil, errors.New("static source: watcher stopped")
+}
+
+func (w *staticWatcher) Stop() error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	return nil
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"github.com/go-lynx/lynx/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"time"
+)
+
+var (
+	// startupBuckets range from 10ms to about 80s, the slow end covers plugins loading under a watchdog
+	startupBuckets = prometheus.ExponentialBuckets(0.01, 2, 14)
+
+	initializeDuration = promauto.With(metrics.Registry()).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lynx_plugin_initialize_duration_seconds",
+		Help:    "Time spent in the Load of a plugin.",
+		Buckets: startupBuckets,
+	}, []string{"plugin"})
+
+	startDuration = promauto.With(metrics.Registry()).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lynx_plugin_start_duration_seconds",
+		Help:    "Time from the start of loading a plugin until it is ready, including pre-warming.",
+		Buckets: startupBuckets,
+	}, []string{"plugin"})
+
+	startTimeouts = promauto.With(metrics.Registry()).NewCounterVec(prometheus.CounterOpts{
+		Name: "lynx_plugin_start_timeouts_total",
+		Help: "Plugins considered hung by the load inactivity watchdog.",
+	}, []string{"plugin"})
+)
+
+// observeSince records the time elapsed since start in the histogram of the named plugin
+func observeSince(h *prometheus.HistogramVec, name string, start time.Time) {
+	h.WithLabelValues(name).Observe(time.Since(start).Seconds())
+}
@@ -71,6 +71,7 @@ func (g *GrpcSubscribe) Subscribe() *gGrpc.ClientConn {
 		grpc.WithMiddleware(
 			logging.Client(app.Lynx().Logger()),
 			tracing.Client(),
+			app.CircuitBreakerClient(g.name),
 		),
 		grpc.WithTLSConfig(g.tlsLoad()),
 		grpc.WithNodeFilter(g.nodeFilter()),
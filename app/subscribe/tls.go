@@ -13,38 +13,43 @@ func (g *GrpcSubscribe) tlsLoad() *tls.Config {
 		return nil
 	}
 
-	certPool := x509.NewCertPool()
-	var rootCA []byte
-
-	if g.rca != "" {
-		// Obtain the root certificate of the remote file
-		if app.Lynx().ControlPlane() == nil {
-			return nil
-		}
-		// if group is empty, use the name as the group name.
-		if g.group == "" {
-			g.group = g.name
-		}
-		s, err := app.Lynx().ControlPlane().Config(g.rca, g.group)
-		if err != nil {
+	if g.rca == "" {
+		// The root certificate of the current application is read on every handshake, a rotated root CA applies
+		// to new connections
+		if _, err := app.RootCAs(); err != nil {
 			panic(err)
 		}
-		c := config.New(
-			config.WithSource(s),
-		)
-		if err := c.Load(); err != nil {
-			panic(err)
-		}
-		var t conf.Cert
-		if err := c.Scan(&t); err != nil {
-			panic(err)
+		return &tls.Config{
+			ServerName:         g.name,
+			InsecureSkipVerify: true,
+			VerifyConnection:   app.VerifyServerCertificate,
 		}
-		rootCA = []byte(t.GetRootCA())
-	} else {
-		rootCA = app.Lynx().Cert().GetRootCA()
 	}
-	// Use the root certificate of the current application directly
-	if !certPool.AppendCertsFromPEM(rootCA) {
+
+	// Obtain the root certificate of the remote file
+	if app.Lynx().ControlPlane() == nil {
+		return nil
+	}
+	// if group is empty, use the name as the group name.
+	if g.group == "" {
+		g.group = g.name
+	}
+	s, err := app.Lynx().ControlPlane().Config(g.rca, g.group)
+	if err != nil {
+		panic(err)
+	}
+	c := config.New(
+		config.WithSource(s),
+	)
+	if err := c.Load(); err != nil {
+		panic(err)
+	}
+	var t conf.Cert
+	if err := c.Scan(&t); err != nil {
+		panic(err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM([]byte(t.GetRootCA())) {
 		panic("Failed to load root certificate")
 	}
 	return &tls.Config{ServerName: g.name, RootCAs: certPool}
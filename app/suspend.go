@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// SuspendPlugin takes a loaded plugin offline without unloading it: its background work is paused through
+// plugin.Quiescer when implemented, its health checks are paused and plugins waiting for it to be ready fail
+func (m *DefaultLynxPluginManager) SuspendPlugin(ctx context.Context, name string) error {
+	m.suspendMu.Lock()
+	defer m.suspendMu.Unlock()
+	p, err := m.loadedPlugin(name)
+	if err != nil {
+		return err
+	}
+	if m.isSuspended(name) {
+		return nil
+	}
+	if q, ok := p.(plugin.Quiescer); ok {
+		if err := q.Quiesce(ctx); err != nil {
+			return fmt.Errorf("suspend %v plugin: %w", name, err)
+		}
+	}
+	m.mu.Lock()
+	if m.suspended == nil {
+		m.suspended = make(map[string]bool)
+	}
+	m.suspended[name] = true
+	m.mu.Unlock()
+	Lynx().PluginHelper(name).Warnf("Plugin %v suspended", name)
+	return nil
+}
+
+// ResumePlugin brings a plugin suspended with SuspendPlugin back online
+func (m *DefaultLynxPluginManager) ResumePlugin(ctx context.Context, name string) error {
+	m.suspendMu.Lock()
+	defer m.suspendMu.Unlock()
+	p, err := m.loadedPlugin(name)
+	if err != nil {
+		return err
+	}
+	if !m.isSuspended(name) {
+		return nil
+	}
+	if q, ok := p.(plugin.Quiescer); ok {
+		if err := q.Resume(ctx); err != nil {
+			return fmt.Errorf("resume %v plugin: %w", name, err)
+		}
+	}
+	m.mu.Lock()
+	delete(m.suspended, name)
+	m.mu.Unlock()
+	Lynx().PluginHelper(name).Infof("Plugin %v resumed", name)
+	return nil
+}
+
+func (m *DefaultLynxPluginManager) isSuspended(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.suspended[name]
+}
+
+// loadedPlugin returns the named plugin when it finished loading
+func (m *DefaultLynxPluginManager) loadedPlugin(name string) (plugin.Plugin, error) {
+	for _, p := range m.loadedPlugins() {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	if _, ok := m.pluginMap[name]; ok {
+		return nil, fmt.Errorf("plugin %v is not loaded", name)
+	}
+	return nil, fmt.Errorf("unknown plugin %v", name)
+}
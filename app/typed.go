@@ -0,0 +1,25 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// GetPlugin returns the named plugin as its concrete type, e.g.
+// app.GetPlugin[*redis.PlugRedis](app.Lynx(), "redis"). It fails when the plugin is not registered or has
+// another type.
+func GetPlugin[T plugin.Plugin](a *LynxApp, name string) (T, error) {
+	var zero T
+	if a == nil || a.PlugManager() == nil {
+		return zero, fmt.Errorf("plugin %v requested before the application was created", name)
+	}
+	p := a.PlugManager().GetPlugin(name)
+	if p == nil {
+		return zero, fmt.Errorf("plugin %v is not registered", name)
+	}
+	t, ok := p.(T)
+	if !ok {
+		return zero, fmt.Errorf("plugin %v is %T, not %T", name, p, zero)
+	}
+	return t, nil
+}
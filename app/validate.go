@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/conf"
+	"github.com/go-lynx/lynx/plugin"
+	"sort"
+	"strings"
+)
+
+// ValidateConfig checks a configuration without loading any plugin: the configuration limits, sections under
+// lynx no registered plugin reads, the schemas of the plugins implementing plugin.SchemaProvider and the
+// dependencies between the plugins. It returns every problem found, nil when the configuration is valid.
+func (m *DefaultLynxPluginManager) ValidateConfig(c config.Config) []error {
+	if err := CheckConfig(c); err != nil {
+		return []error{err}
+	}
+	errs := m.unknownSections(c)
+	if err := m.preparePlugDryRun(c); err != nil {
+		return append(errs, err)
+	}
+
+	for _, p := range m.pluginList {
+		s, ok := p.(plugin.SchemaProvider)
+		if !ok {
+			continue
+		}
+		// Configurations in a deprecated format are valid as long as the plugin migrates them
+		value, err := pluginConfig(p, c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", p.ConfPrefix(), err))
+			continue
+		}
+		var v interface{}
+		if err := value.Scan(&v); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", p.ConfPrefix(), err))
+			continue
+		}
+		schemaErrs, err := validateSchema(s.ConfigSchema(), p.ConfPrefix(), v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, schemaErrs...)
+	}
+
+	if _, err := m.DependencyGraph(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// preparePlugDryRun creates the configured plugins, turning the panics of PreparePlug into an error
+func (m *DefaultLynxPluginManager) preparePlugDryRun(c config.Config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	m.PreparePlug(c)
+	return nil
+}
+
+// unknownSections reports the sections under lynx that are neither framework settings nor read by a registered
+// plugin, typically misspelled plugin names
+func (m *DefaultLynxPluginManager) unknownSections(c config.Config) []error {
+	sections, err := c.Value("lynx").Map()
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]bool)
+	fields := (&conf.Lynx{}).ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		known[string(fields.Get(i).Name())] = true
+		known[fields.Get(i).JSONName()] = true
+	}
+	for prefix := range m.factory.GetRegisterTable() {
+		if section, ok := strings.CutPrefix(prefix, "lynx."); ok {
+			known[strings.SplitN(section, ".", 2)[0]] = true
+		}
+	}
+
+	var unknown []string
+	for k := range sections {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	errs := make([]error, len(unknown))
+	for i, k := range unknown {
+		errs[i] = fmt.Errorf("lynx.%v: no registered plugin reads this section", k)
+	}
+	return errs
+}
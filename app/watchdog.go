@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+	"time"
+)
+
+// loadPlugin loads a single plugin. When a load inactivity timeout is configured the plugin is loaded under a
+// watchdog, so a plugin that keeps reporting progress through Heartbeat may take as long as it needs, while a
+// plugin that stays silent for longer than the timeout is reported as hung. Load can't be interrupted, the load
+// of a hung plugin is abandoned: should it still return, the plugin is unloaded again, see reapAbandoned.
+func (m *DefaultLynxPluginManager) loadPlugin(p plugin.Plugin, conf config.Config) error {
+	value, err := migrateConfig(p, conf.Value(p.ConfPrefix()))
+	if err != nil {
+		return err
+	}
+
+	timeout := Lynx().pluginsConf().GetLoadInactivityTimeout().AsDuration()
+	if timeout <= 0 {
+		_, err := p.Load(value)
+		return err
+	}
+
+	// Loading a plugin counts as progress for the plugins that are loading it, e.g. a control plane
+	// plugin that loads the remaining plugins from its own Load.
+	m.heartbeatAll(fmt.Sprintf("loading plugin %v", p.Name()))
+
+	beat := make(chan string, 1)
+	m.mu.Lock()
+	m.heartbeats[p.Name()] = beat
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.heartbeats, p.Name())
+		m.mu.Unlock()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Load(value)
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case progress := <-beat:
+			m.progress.beat(p.Name(), progress)
+			Lynx().PluginHelper(p.Name()).Infof("Plugin %v is still loading: %v", p.Name(), progress)
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			startTimeouts.WithLabelValues(p.Name()).Inc()
+			m.mu.Lock()
+			if m.abandoned == nil {
+				m.abandoned = make(map[string]bool)
+			}
+			m.abandoned[p.Name()] = true
+			m.mu.Unlock()
+			go m.reapAbandoned(p, done)
+			return fmt.Errorf("plugin %v reported no progress for %v and is considered hung", p.Name(), timeout)
+		}
+	}
+}
+
+// reapAbandoned waits for the abandoned load of a hung plugin. A load that still succeeds left the plugin
+// holding resources the application doesn't use, so it is unloaded again. A load that never returns keeps its
+// goroutine for the lifetime of the process.
+func (m *DefaultLynxPluginManager) reapAbandoned(p plugin.Plugin, done <-chan error) {
+	err := <-done
+	defer func() {
+		m.mu.Lock()
+		delete(m.abandoned, p.Name())
+		m.mu.Unlock()
+	}()
+	if err != nil {
+		Lynx().PluginHelper(p.Name()).Warnf("Abandoned load of hung plugin %v failed: %v", p.Name(), err)
+		return
+	}
+	Lynx().PluginHelper(p.Name()).Warnf("Abandoned load of hung plugin %v returned, unloading it", p.Name())
+	if err := p.Unload(); err != nil {
+		Lynx().PluginHelper(p.Name()).Errorf("Exception in uninstalling %v plugin : %v", p.Name(), err)
+	}
+}
+
+// Heartbeat reports loading progress of the named plugin and resets its inactivity watchdog.
+// It is a no-op when the plugin is not being loaded or the watchdog is disabled.
+func (m *DefaultLynxPluginManager) Heartbeat(name string, progress string) {
+	m.mu.Lock()
+	beat := m.heartbeats[name]
+	m.mu.Unlock()
+	if beat == nil {
+		return
+	}
+	select {
+	case beat <- progress:
+	default:
+	}
+}
+
+// heartbeatAll reports progress to every plugin that is currently loading.
+func (m *DefaultLynxPluginManager) heartbeatAll(progress string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, beat := range m.heartbeats {
+		select {
+		case beat <- progress:
+		default:
+		}
+	}
+}
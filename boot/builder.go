@@ -0,0 +1,143 @@
+package boot
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/conf"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+// Builder assembles a Lynx application without a hand written wire function: the Kratos application is created
+// from the servers of the loaded plugins and the service registry of the control plane
+type Builder struct {
+	name     string
+	version  string
+	plugins  []plugin.Plugin
+	sources  []config.Source
+	metadata map[string]string
+	secrets  SecretResolver
+}
+
+// NewBuilder creates a Builder, without config sources the local bootstrap configuration given by -conf is used
+func NewBuilder() *Builder {
+	return &Builder{
+		metadata: map[string]string{},
+	}
+}
+
+// WithName sets the application name, it takes precedence over lynx.application.name
+func (b *Builder) WithName(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// WithVersion sets the application version, it takes precedence over lynx.application.version
+func (b *Builder) WithVersion(version string) *Builder {
+	b.version = version
+	return b
+}
+
+// WithPlugins adds plugins that are loaded in addition to the ones enabled by configuration
+func (b *Builder) WithPlugins(p ...plugin.Plugin) *Builder {
+	b.plugins = append(b.plugins, p...)
+	return b
+}
+
+// WithConfigSources sets the bootstrap configuration sources, later sources override earlier ones
+func (b *Builder) WithConfigSources(s ...config.Source) *Builder {
+	b.sources = append(b.sources, s...)
+	return b
+}
+
+// WithMetadata adds service metadata published through the service registry
+func (b *Builder) WithMetadata(md map[string]string) *Builder {
+	for k, v := range md {
+		b.metadata[k] = v
+	}
+	return b
+}
+
+// WithSecretResolver sets the resolver of ${secret:path} placeholders in the bootstrap configuration
+func (b *Builder) WithSecretResolver(r SecretResolver) *Builder {
+	b.secrets = r
+	return b
+}
+
+// Build loads the bootstrap configuration, validates it and returns the application ready to Run
+func (b *Builder) Build() (*Boot, error) {
+	sources := b.sources
+	if len(sources) == 0 {
+		parseFlags()
+		sources = []config.Source{file.NewSource(flagConf)}
+	}
+	if b.name != "" || b.version != "" {
+		override, err := applicationSource(b.name, b.version)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, override)
+	}
+
+	c := config.New(config.WithSource(sources...), config.WithResolver(interpolate(b.secrets)))
+	if err := c.Load(); err != nil {
+		return nil, errors.New("lynx builder: failed to load the bootstrap configuration: " + err.Error())
+	}
+	var bootConf conf.Bootstrap
+	if err := c.Scan(&bootConf); err != nil {
+		return nil, errors.New("lynx builder: invalid bootstrap configuration: " + err.Error())
+	}
+	if bootConf.GetLynx().GetApplication().GetName() == "" {
+		return nil, errors.New("lynx builder: the application name is required, use WithName or set lynx.application.name")
+	}
+	if bootConf.GetLynx().GetApplication().GetVersion() == "" {
+		return nil, errors.New("lynx builder: the application version is required, use WithVersion or set lynx.application.version")
+	}
+
+	return &Boot{
+		wire:    b.wire,
+		plugins: b.plugins,
+		conf:    c,
+		secrets: b.secrets,
+	}, nil
+}
+
+// wire creates the Kratos application from the servers of the loaded plugins, registered with the service registry
+// of the control plane through KratosOptions
+func (b *Builder) wire(logger log.Logger) (*kratos.App, error) {
+	servers := app.Lynx().PlugManager().Servers()
+	if len(servers) == 0 {
+		return nil, errors.New("lynx builder: no server plugin is loaded, enable lynx.http or lynx.grpc")
+	}
+	opts := []kratos.Option{
+		kratos.ID(app.Host()),
+		kratos.Name(app.Name()),
+		kratos.Version(app.Version()),
+		kratos.Metadata(b.metadata),
+		kratos.Logger(logger),
+		kratos.Server(servers...),
+	}
+	return kratos.New(append(opts, KratosOptions()...)...), nil
+}
+
+// applicationSource is an in-memory config source overriding the application name and version
+func applicationSource(name, version string) (config.Source, error) {
+	application := map[string]string{}
+	if name != "" {
+		application["name"] = name
+	}
+	if version != "" {
+		application["version"] = version
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"lynx": map[string]interface{}{"application": application},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return app.NewStaticSource("lynx-builder", data, "json"), nil
+}
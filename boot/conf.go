@@ -4,10 +4,13 @@ import (
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-kratos/kratos/v2/config/file"
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
 )
 
-// loadLocalBootFile Boot configuration file for service startup loaded from local
+// loadLocalBootFile Boot configuration file for service startup loaded from local, it stays open to watch the
+// file for changes until closeConfig
 func (b *Boot) loadLocalBootFile() {
+	parseFlags()
 	// 打印日志，指示 Lynx 正在读取本地启动配置文件或文件夹
 	log.Info("Lynx reading local bootstrap configuration file/folder:" + flagConf)
 
@@ -16,6 +19,8 @@ func (b *Boot) loadLocalBootFile() {
 		config.WithSource(
 			file.NewSource(flagConf),
 		),
+		// 展开环境变量与密钥占位符，缺少的环境变量直接报错
+		config.WithResolver(interpolate(b.secrets)),
 	)
 
 	// 加载配置，如果加载过程中发生错误，抛出 panic
@@ -23,15 +28,22 @@ func (b *Boot) loadLocalBootFile() {
 		panic(err)
 	}
 
-	// 延迟关闭本地文件，确保在函数结束时关闭文件
-	defer func(c config.Config) {
-		// 关闭配置对象，如果关闭过程中发生错误，抛出 panic
-		err := c.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(c)
-
+	// 配置在应用运行期间保持打开，文件变化才能推送到插件，由 cleanup 关闭
 	// 将加载的配置对象保存到 Boot 结构体的 conf 字段中
 	b.conf = c
 }
+
+// closeConfig closes the configuration the application runs on, a configuration replaced by a reload was closed
+// by the reload already
+func (b *Boot) closeConfig() {
+	c := b.conf
+	if app.Lynx() != nil && app.Lynx().GlobalConfig() != nil {
+		c = app.Lynx().GlobalConfig()
+	}
+	if c == nil {
+		return
+	}
+	if err := c.Close(); err != nil {
+		log.Warnf("Failed to close the configuration: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package boot
+
+import (
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type configurableJobPlugin struct {
+	jobPlugin
+	addrs chan string
+}
+
+func (c *configurableJobPlugin) Configure(v config.Value) error {
+	var conf struct {
+		Addr string `json:"addr"`
+	}
+	if err := v.Scan(&conf); err != nil {
+		return err
+	}
+	c.addrs <- conf.Addr
+	return nil
+}
+
+func TestLocalBootFileHotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(addr string) {
+		data := `{"lynx": {"application": {"name": "test", "version": "v1", "close_banner": true}, ` +
+			`"plugins": {"reload_debounce": "0.01s"}, "db": {"addr": "` + addr + `"}}}`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("db-1")
+	parseFlags()
+	prev := flagConf
+	flagConf = path
+	defer func() {
+		flagConf = prev
+	}()
+
+	b := &Boot{}
+	b.loadLocalBootFile()
+	db := &configurableJobPlugin{jobPlugin: jobPlugin{name: "db"}, addrs: make(chan string, 1)}
+	a := app.NewApp(b.conf, db, &jobPlugin{name: "cache"})
+	a.SetLogger(log.NewStdLogger(io.Discard))
+	a.InitLogger()
+	changes := make(chan app.ConfigChange, 1)
+	a.PlugManager().OnConfigChange(func(change app.ConfigChange) {
+		changes <- change
+	})
+	a.PlugManager().LoadPlugins(b.conf)
+	defer func() {
+		_ = b.cleanup(nil)
+	}()
+
+	write("db-2")
+	select {
+	case addr := <-db.addrs:
+		if addr != "db-2" {
+			t.Errorf("Expected db to be configured with db-2, but got %v", addr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected db to be reconfigured after the file changed")
+	}
+	select {
+	case change := <-changes:
+		if change.Plugin != "db" || change.Error != "" {
+			t.Errorf("Expected db to be reconfigured, but got %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the configuration change to be reported")
+	}
+}
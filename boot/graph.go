@@ -0,0 +1,23 @@
+package boot
+
+import (
+	"fmt"
+	"github.com/go-lynx/lynx/app"
+	"os"
+)
+
+// printGraph writes the plugin dependency graph of the configuration to stdout in DOT format, without loading
+// any plugin. Unresolvable dependencies are reported on stderr and make the process exit with 1.
+func (b *Boot) printGraph() {
+	if b.conf == nil {
+		b.loadLocalBootFile()
+	}
+	app.NewApp(b.conf, b.plugins...)
+	app.Lynx().PlugManager().PreparePlug(b.conf)
+	graph, err := app.Lynx().PlugManager().DependencyGraph()
+	fmt.Print(graph.ToDOT())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
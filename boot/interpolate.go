@@ -0,0 +1,151 @@
+package boot
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// placeholder matches ${...} in configuration values
+var placeholder = regexp.MustCompile(`\${([^}]*)}`)
+
+// SecretResolver resolves ${secret:path} placeholders in the bootstrap configuration, back it with a secret
+// store such as Vault to keep credentials out of committed configuration files
+type SecretResolver interface {
+	Resolve(path string) (string, error)
+}
+
+// WithSecretResolver sets the resolver of ${secret:path} placeholders in the bootstrap configuration
+func (b *Boot) WithSecretResolver(r SecretResolver) *Boot {
+	b.secrets = r
+	return b
+}
+
+// interpolate returns a config resolver expanding the placeholders of string values:
+//
+//	${secret:path}   the secret at path, resolved by secrets
+//	${NAME:-value}   the environment variable NAME, value when it is not set
+//	${NAME}          the configuration key NAME or else the environment variable NAME, it fails when neither is set
+//	${NAME:value}    the configuration key NAME or else the environment variable NAME, value when neither is set
+func interpolate(secrets SecretResolver) config.Resolver {
+	return func(input map[string]interface{}) error {
+		r := &interpolator{root: input, secrets: secrets}
+		return r.walk("", input)
+	}
+}
+
+type interpolator struct {
+	root    map[string]interface{}
+	secrets SecretResolver
+}
+
+func (r *interpolator) walk(path string, v interface{}) error {
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range vt {
+			if s, ok := sub.(string); ok {
+				expanded, err := r.expand(join(path, k), s)
+				if err != nil {
+					return err
+				}
+				vt[k] = expanded
+				continue
+			}
+			if err := r.walk(join(path, k), sub); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, sub := range vt {
+			key := fmt.Sprintf("%v[%v]", path, i)
+			if s, ok := sub.(string); ok {
+				expanded, err := r.expand(key, s)
+				if err != nil {
+					return err
+				}
+				vt[i] = expanded
+				continue
+			}
+			if err := r.walk(key, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expand replaces the placeholders of the value at key
+func (r *interpolator) expand(key, s string) (string, error) {
+	var err error
+	expanded := placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		if err != nil {
+			return m
+		}
+		var v string
+		v, err = r.resolve(key, strings.TrimSpace(m[2:len(m)-1]))
+		return v
+	})
+	return expanded, err
+}
+
+func (r *interpolator) resolve(key, expr string) (string, error) {
+	if path, ok := strings.CutPrefix(expr, "secret:"); ok {
+		if r.secrets == nil {
+			return "", fmt.Errorf("config %v: no secret resolver for ${secret:%v}, set one with WithSecretResolver", key, path)
+		}
+		v, err := r.secrets.Resolve(path)
+		if err != nil {
+			return "", fmt.Errorf("config %v: resolving secret %v: %w", key, path, err)
+		}
+		return v, nil
+	}
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		if v, ok := os.LookupEnv(expr[:i]); ok {
+			return v, nil
+		}
+		return expr[i+2:], nil
+	}
+	name, def, hasDefault := expr, "", false
+	if i := strings.Index(expr, ":"); i >= 0 {
+		name, def, hasDefault = expr[:i], expr[i+1:], true
+	}
+	if v, ok := r.lookup(name); ok {
+		return v, nil
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	if hasDefault {
+		return def, nil
+	}
+	return "", fmt.Errorf("config %v: environment variable %v is not set, give a default with ${%v:-default}", key, name, name)
+}
+
+// lookup reads a configuration key such as lynx.application.name, keeping the Kratos ${key:default} placeholders
+// working
+func (r *interpolator) lookup(name string) (string, bool) {
+	var v interface{} = r.root
+	for _, k := range strings.Split(name, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if v, ok = m[k]; !ok {
+			return "", false
+		}
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,99 @@
+package boot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type secrets map[string]string
+
+func (s secrets) Resolve(path string) (string, error) {
+	if v, ok := s[path]; ok {
+		return v, nil
+	}
+	return "", errors.New("permission denied")
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("LYNX_TEST_HOST", "10.0.0.1")
+	t.Setenv("LYNX_TEST_EMPTY", "")
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"environment variable", "${LYNX_TEST_HOST}:6379", "10.0.0.1:6379"},
+		{"environment default unused", "${LYNX_TEST_HOST:-127.0.0.1}", "10.0.0.1"},
+		{"environment default", "${LYNX_TEST_MISSING:-127.0.0.1}", "127.0.0.1"},
+		{"empty environment variable", "${LYNX_TEST_EMPTY:-fallback}", ""},
+		{"configuration key", "${lynx.application.name}-worker", "orders-worker"},
+		{"configuration key default", "${lynx.application.zone:eu-1}", "eu-1"},
+		{"secret", "postgres://app:${secret:db/password}@db", "postgres://app:s3cret@db"},
+		{"several placeholders", "${LYNX_TEST_HOST}/${ lynx.application.name }", "10.0.0.1/orders"},
+		{"no placeholder", "plain", "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := map[string]interface{}{
+				"lynx": map[string]interface{}{
+					"application": map[string]interface{}{"name": "orders"},
+					"value":       tt.value,
+				},
+			}
+			if err := interpolate(secrets{"db/password": "s3cret"})(c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := c["lynx"].(map[string]interface{})["value"]; got != tt.want {
+				t.Errorf("Expected %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpolateNested(t *testing.T) {
+	t.Setenv("LYNX_TEST_BROKER", "kafka:9092")
+	c := map[string]interface{}{
+		"lynx": map[string]interface{}{
+			"kafka": map[string]interface{}{
+				"brokers": []interface{}{"${LYNX_TEST_BROKER}", map[string]interface{}{"addr": "${LYNX_TEST_BROKER}"}},
+				"port":    9092,
+			},
+		},
+	}
+	if err := interpolate(nil)(c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	brokers := c["lynx"].(map[string]interface{})["kafka"].(map[string]interface{})["brokers"].([]interface{})
+	if brokers[0] != "kafka:9092" || brokers[1].(map[string]interface{})["addr"] != "kafka:9092" {
+		t.Errorf("Expected the brokers to be expanded, but got %v", brokers)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets SecretResolver
+		value   string
+		want    string
+	}{
+		{"missing variable", nil, "${LYNX_TEST_MISSING}",
+			"config lynx.redis.addr: environment variable LYNX_TEST_MISSING is not set, give a default with ${LYNX_TEST_MISSING:-default}"},
+		{"no secret resolver", nil, "${secret:redis/password}",
+			"config lynx.redis.addr: no secret resolver for ${secret:redis/password}, set one with WithSecretResolver"},
+		{"failing secret resolver", secrets{}, "${secret:redis/password}",
+			"config lynx.redis.addr: resolving secret redis/password: permission denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := map[string]interface{}{
+				"lynx": map[string]interface{}{"redis": map[string]interface{}{"addr": tt.value}},
+			}
+			err := interpolate(tt.secrets)(c)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error %q, but got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package boot
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Job is a one-off task run with the plugins of the application, e.g. a backfill.
+// ctx is cancelled when the process is interrupted.
+type Job func(ctx context.Context) error
+
+var (
+	jobsMu sync.Mutex
+	jobs   = make(map[string]Job)
+)
+
+// RegisterJob makes a job available to RunJob and the -job flag
+func RegisterJob(name string, job Job) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	jobs[name] = job
+}
+
+// RunJob loads the configured plugins except the ones serving traffic, runs the named job and unloads the
+// plugins again. No servers are started.
+func (b *Boot) RunJob(name string) (err error) {
+	jobsMu.Lock()
+	job, ok := jobs[name]
+	jobsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown job %v", name)
+	}
+
+	// Only the plugins loaded for the job are unloaded, the server plugins were never loaded
+	var load []string
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+		if app.Lynx() != nil && app.Lynx().PlugManager() != nil {
+			app.Lynx().PlugManager().UnloadPluginsByName(load)
+		}
+		b.closeConfig()
+	}()
+
+	if b.conf == nil {
+		b.loadLocalBootFile()
+	}
+	app.NewApp(b.conf, b.plugins...)
+	app.Lynx().InitLogger()
+	m := app.Lynx().PlugManager()
+	names := m.PreparePlug(b.conf)
+	for _, p := range b.plugins {
+		names = append(names, p.Name())
+	}
+	for _, n := range names {
+		if _, serves := m.GetPlugin(n).(plugin.ServerProvider); !serves && m.GetPlugin(n) != nil {
+			load = append(load, n)
+		}
+	}
+	m.LoadPluginsByName(load, b.conf)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	app.Lynx().Helper().Infof("Running job %v", name)
+	st := time.Now()
+	if err := job(ctx); err != nil {
+		app.Lynx().Helper().Errorf("Job %v failed after %v: %v", name, time.Since(st), err)
+		return err
+	}
+	app.Lynx().Helper().Infof("Job %v finished in %v", name, time.Since(st))
+	return nil
+}
+
+// runJobFlag runs the job named by the -job flag and exits
+func (b *Boot) runJobFlag() {
+	if err := b.RunJob(flagJob); err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+}
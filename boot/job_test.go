@@ -0,0 +1,87 @@
+package boot
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin"
+	"testing"
+)
+
+type jobPlugin struct {
+	name     string
+	loaded   bool
+	unloaded bool
+}
+
+func (j *jobPlugin) Name() string {
+	return j.name
+}
+
+func (j *jobPlugin) DependsOn(config.Value) []string {
+	return nil
+}
+
+func (j *jobPlugin) Weight() int {
+	return 0
+}
+
+func (j *jobPlugin) ConfPrefix() string {
+	return "lynx." + j.name
+}
+
+func (j *jobPlugin) Load(config.Value) (plugin.Plugin, error) {
+	j.loaded = true
+	return j, nil
+}
+
+func (j *jobPlugin) Unload() error {
+	j.unloaded = true
+	return nil
+}
+
+type serverPlugin struct {
+	jobPlugin
+}
+
+func (s *serverPlugin) Servers() []transport.Server {
+	return nil
+}
+
+func TestRunJobWithoutServers(t *testing.T) {
+	data := []byte(`{"lynx": {"application": {"name": "test", "version": "v1", "close_banner": true}}}`)
+	c := config.New(config.WithSource(app.NewStaticSource("test", data, "json")))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	db := &jobPlugin{name: "db"}
+	http := &serverPlugin{jobPlugin{name: "http"}}
+	ran := false
+	RegisterJob("test", func(ctx context.Context) error {
+		ran = true
+		if !db.loaded {
+			t.Errorf("Expected db to be loaded while the job runs")
+		}
+		return nil
+	})
+
+	b := &Boot{conf: c, plugins: []plugin.Plugin{db, http}}
+	if err := b.RunJob("test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !ran {
+		t.Errorf("Expected the job to run")
+	}
+	if !db.unloaded {
+		t.Errorf("Expected db to be unloaded after the job")
+	}
+	if http.loaded || http.unloaded {
+		t.Errorf("Expected the server plugin to be neither loaded nor unloaded")
+	}
+	if err := b.RunJob("missing"); err == nil {
+		t.Errorf("Expected an error for an unknown job")
+	}
+}
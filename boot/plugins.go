@@ -0,0 +1,38 @@
+package boot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin"
+	"os"
+)
+
+// pluginInfo describes a plugin of the configuration for tools such as lynx doctor diff
+type pluginInfo struct {
+	Name       string `json:"name"`
+	ConfPrefix string `json:"conf_prefix"`
+	// Configurable plugins apply configuration changes without a restart
+	Configurable bool `json:"configurable"`
+}
+
+// printPlugins writes the plugins of the configuration to stdout as JSON, without loading any of them
+func (b *Boot) printPlugins() {
+	if b.conf == nil {
+		b.loadLocalBootFile()
+	}
+	app.NewApp(b.conf, b.plugins...)
+	plugins := append([]plugin.Plugin(nil), b.plugins...)
+	for _, name := range app.Lynx().PlugManager().PreparePlug(b.conf) {
+		plugins = append(plugins, app.Lynx().PlugManager().GetPlugin(name))
+	}
+	infos := make([]pluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		_, configurable := p.(plugin.Configurable)
+		infos = append(infos, pluginInfo{Name: p.Name(), ConfPrefix: p.ConfPrefix(), Configurable: configurable})
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(infos); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
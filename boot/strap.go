@@ -1,31 +1,44 @@
 package boot
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-kratos/kratos/v2/encoding/json"
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
 	"github.com/go-lynx/lynx/app"
 	"github.com/go-lynx/lynx/plugin"
 	"google.golang.org/protobuf/encoding/protojson"
+	"os"
+	"os/signal"
 	"time"
 )
 
 var (
-	flagConf string
+	flagConf     string
+	flagJob      string
+	flagGraph    bool
+	flagValidate bool
+	flagPlugins  bool
 )
 
 type Boot struct {
 	wire    wireApp
 	plugins []plugin.Plugin
 	conf    config.Config
+	secrets SecretResolver
+	logger  log.Logger
 }
 
 func init() {
 	flag.StringVar(&flagConf, "conf", "../../configs", "config path, eg: -conf config.yaml")
-	flag.Parse()
+	flag.StringVar(&flagJob, "job", "", "run a registered job instead of serving, eg: -job backfill")
+	flag.BoolVar(&flagGraph, "graph", false, "print the plugin dependency graph in DOT format and exit")
+	flag.BoolVar(&flagValidate, "validate", false, "validate the configuration against the plugins and exit")
+	flag.BoolVar(&flagPlugins, "plugins", false, "print the plugins of the configuration as JSON and exit")
 	json.MarshalOptions = protojson.MarshalOptions{
 		EmitUnpopulated: true,
 		UseProtoNames:   true,
@@ -34,17 +47,133 @@ func init() {
 
 type wireApp func(logger log.Logger) (*kratos.App, error)
 
+// parseFlags parses the command line on first use rather than in init, which would fail for programs and tests
+// defining flags of their own
+func parseFlags() {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+}
+
 // Run 方法是应用程序的启动入口点
 func (b *Boot) Run() {
+	parseFlags()
+	// 指定了 -graph 时只输出插件依赖图
+	if flagGraph {
+		b.printGraph()
+		return
+	}
+	// 指定了 -validate 时只校验配置
+	if flagValidate {
+		b.validate()
+		return
+	}
+	// 指定了 -plugins 时只输出配置启用的插件
+	if flagPlugins {
+		b.printPlugins()
+		return
+	}
+	// 指定了 -job 时只运行一次性任务，不启动服务
+	if flagJob != "" {
+		b.runJobFlag()
+		return
+	}
 	// 延迟调用 handlePanic 方法，用于处理可能发生的 panic
 	defer b.handlePanic()
+	k := b.start()
+
+	// 收到重启信号时启动新进程并交接监听器，当前进程停止并处理完剩余请求
+	go b.handleRestart(k)
+
+	// 启动 Kratos 应用，并等待停止信号
+	if err := k.Run(); err != nil {
+		// 如果发生错误，记录错误信息并抛出 panic
+		app.Lynx().Helper().Error(err)
+		panic(err)
+	}
+}
+
+// RunOption configures RunContext
+type RunOption func(o *runOptions)
+
+type runOptions struct {
+	signals bool
+}
+
+// running holds the options of the running application, read by KratosOptions
+var running = runOptions{signals: true}
+
+// noSignal is never delivered, subscribing the Kratos application to it alone keeps it from handling the default
+// stop signals
+type noSignal struct{}
+
+func (noSignal) String() string {
+	return "no signal"
+}
+
+func (noSignal) Signal() {}
+
+// WithoutSignals leaves signal handling to the embedding program: Lynx doesn't react to restart signals and the
+// Kratos application doesn't stop on SIGINT or SIGTERM, the program stops it by cancelling the context of
+// RunContext. Applications wired by hand need KratosOptions for the latter.
+func WithoutSignals() RunOption {
+	return func(o *runOptions) {
+		o.signals = false
+	}
+}
+
+// RunContext runs the application like Run until ctx is cancelled or the application stops, then unloads the
+// plugins within the shutdown budget. It returns the startup, run or unload error instead of panicking, so Lynx
+// can be embedded in programs with their own lifecycle management.
+func (b *Boot) RunContext(ctx context.Context, opts ...RunOption) (err error) {
+	o := runOptions{signals: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	running = o
+	defer func() {
+		if cleanupErr := b.cleanup(recover()); err == nil {
+			err = cleanupErr
+		}
+	}()
+	k := b.start()
+	if o.signals {
+		go b.handleRestart(k)
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			app.Lynx().Helper().Infof("Context cancelled, stopping Lynx application")
+			if err := k.Stop(); err != nil {
+				app.Lynx().Helper().Error(err)
+			}
+		case <-stopped:
+		}
+	}()
+	if err := k.Run(); err != nil {
+		app.Lynx().Helper().Error(err)
+		return err
+	}
+	return nil
+}
+
+// start loads the configuration and the plugins and wires the Kratos application, it panics on failure
+func (b *Boot) start() *kratos.App {
 	// 记录当前时间，用于计算启动耗时
 	st := time.Now()
 
-	// 加载本地启动配置文件
-	b.loadLocalBootFile()
+	// 加载本地启动配置文件，通过 Builder 构建时配置已经加载
+	if b.conf == nil {
+		b.loadLocalBootFile()
+	}
 	// 创建一个新的 Lynx 应用实例，传入配置和插件
 	app.NewApp(b.conf, b.plugins...)
+	if b.logger != nil {
+		app.Lynx().SetLogger(b.logger)
+	}
 	// 初始化 Lynx 应用的日志记录器
 	app.Lynx().InitLogger()
 	// 记录一条信息，指示 Lynx 应用正在启动
@@ -62,25 +191,101 @@ func (b *Boot) Run() {
 		panic(err)
 	}
 
+	// 等待服务发现中注册了足够的实例，未配置时直接跳过
+	app.WaitForPeers(context.Background())
+
 	// 计算启动耗时（毫秒）
 	t := (time.Now().UnixNano() - st.UnixNano()) / 1e6
 	// 记录一条信息，指示 Lynx 应用启动成功，并显示启动耗时
 	app.Lynx().Helper().Infof("Lynx application started successfully，elapsed time：%v ms, port listening initiated.", t)
+	return k
+}
 
-	// 启动 Kratos 应用，并等待停止信号
-	if err := k.Run(); err != nil {
-		// 如果发生错误，记录错误信息并抛出 panic
-		app.Lynx().Helper().Error(err)
-		panic(err)
+// handleRestart re-spawns the application on a restart signal, the new process inherits the listeners and the
+// current one stops gracefully
+func (b *Boot) handleRestart(k *kratos.App) {
+	if len(app.RestartSignals) == 0 {
+		return
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, app.RestartSignals...)
+	for range c {
+		pid, err := app.Restart()
+		if err != nil {
+			app.Lynx().Helper().Errorf("Graceful restart failed, continuing to serve: %v", err)
+			continue
+		}
+		app.Lynx().Helper().Infof("Graceful restart started process %v, stopping this process", pid)
+		signal.Stop(c)
+		if err := k.Stop(); err != nil {
+			app.Lynx().Helper().Error(err)
+		}
+		return
 	}
 }
 
+// KratosOptions returns the options Lynx needs on the Kratos application: the instance is deregistered from the
+// service registry of the control plane before the plugins drain their active requests and the servers stop, and
+// stop signals are left to the embedding program when run WithoutSignals. The Builder adds them itself, hand
+// written wire functions pass them last to kratos.New, as they set the registrar of the control plane.
+func KratosOptions() []kratos.Option {
+	var opts []kratos.Option
+	if r := app.ServiceRegistry(); r != nil {
+		opts = append(opts, kratos.Registrar(drainingRegistrar{r}))
+	} else {
+		// Without a registry nothing routes traffic to the instance, the plugins drain right before the servers stop
+		opts = append(opts, kratos.BeforeStop(func(ctx context.Context) error {
+			drain()
+			return nil
+		}))
+	}
+	if !running.signals {
+		opts = append(opts, kratos.Signal(noSignal{}))
+	}
+	return opts
+}
+
+// drainingRegistrar drains the plugins once the instance is deregistered, the Kratos application stops its servers
+// right after deregistering. Draining earlier, e.g. in a BeforeStop hook, would keep the instance in service
+// discovery and new requests coming in until the servers are gone. After a graceful restart the registration
+// belongs to the new process and is kept.
+type drainingRegistrar struct {
+	registry.Registrar
+}
+
+func (r drainingRegistrar) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	var err error
+	if app.HandedOver() {
+		app.Lynx().Helper().Infof("Keeping the registration of %v, it was handed over to the restarted process", service.ID)
+	} else {
+		err = r.Registrar.Deregister(ctx, service)
+	}
+	drain()
+	return err
+}
+
+// drain lets the plugins finish their active requests within lynx.shutdown.drain_timeout
+func drain() {
+	if app.Lynx() == nil || app.Lynx().PlugManager() == nil {
+		return
+	}
+	app.Lynx().PlugManager().Drain(context.Background())
+}
+
 // handlePanic 方法用于处理应用程序运行过程中可能发生的 panic
 func (b *Boot) handlePanic() {
+	_ = b.cleanup(recover())
+}
+
+// cleanup logs a recovered panic, unloads the plugins within the shutdown budget and closes the configuration, it
+// returns the panic as an error or else the unload error
+func (b *Boot) cleanup(r interface{}) error {
+	var err error
 	// 捕获 recover() 函数返回的 panic 信息
-	if r := recover(); r != nil {
+	if r != nil {
 		// 将 recover() 返回的结果转换为 error 类型
-		err, ok := r.(error)
+		var ok bool
+		err, ok = r.(error)
 		// 如果转换失败，则将 recover() 返回的结果转换为字符串，并包装成 error 类型
 		if !ok {
 			err = fmt.Errorf("%v", r)
@@ -96,8 +301,20 @@ func (b *Boot) handlePanic() {
 
 	// 无论是否发生 panic，都卸载插件
 	if app.Lynx() != nil && app.Lynx().PlugManager() != nil {
-		app.Lynx().PlugManager().UnloadPlugins()
+		ctx, cancel := app.ShutdownContext()
+		defer cancel()
+		if unloadErr := app.Lynx().PlugManager().UnloadPluginsContext(ctx); err == nil {
+			err = unloadErr
+		}
 	}
+	b.closeConfig()
+	return err
+}
+
+// WithLogger sets the sink Lynx logs to instead of standard output, e.g. a JSON or OpenTelemetry logger
+func (b *Boot) WithLogger(logger log.Logger) *Boot {
+	b.logger = logger
+	return b
 }
 
 // LynxApplication Create a Lynx microservice bootstrap program
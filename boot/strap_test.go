@@ -0,0 +1,135 @@
+package boot
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-lynx/lynx/app"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// The process started by app.Restart runs the test binary again, it exits right away
+	if os.Getenv("LYNX_INHERITED_LISTENERS") != "" {
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// events records the shutdown steps in the order they happen
+type events struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (e *events) add(step string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.steps = append(e.steps, step)
+}
+
+func (e *events) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.steps, ",")
+}
+
+type eventRegistrar struct {
+	events     *events
+	registered chan struct{}
+}
+
+func (r *eventRegistrar) Register(context.Context, *registry.ServiceInstance) error {
+	close(r.registered)
+	return nil
+}
+
+func (r *eventRegistrar) Deregister(context.Context, *registry.ServiceInstance) error {
+	r.events.add("deregister")
+	return nil
+}
+
+type registryControlPlane struct {
+	app.LocalControlPlane
+	registrar registry.Registrar
+}
+
+func (c *registryControlPlane) NewServiceRegistry() registry.Registrar {
+	return c.registrar
+}
+
+type drainPlugin struct {
+	jobPlugin
+	events *events
+}
+
+func (d *drainPlugin) Drain(context.Context) error {
+	d.events.add("drain")
+	return nil
+}
+
+// runAndStop runs a Kratos application with the boot options until its instance is registered, stops it and
+// returns the shutdown steps
+func runAndStop(t *testing.T) string {
+	data := []byte(`{"lynx": {"application": {"name": "test", "version": "v1", "close_banner": true}}}`)
+	c := config.New(config.WithSource(app.NewStaticSource("test", data, "json")))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	e := &events{}
+	r := &eventRegistrar{events: e, registered: make(chan struct{})}
+	a := app.NewApp(c, &drainPlugin{jobPlugin: jobPlugin{name: "server"}, events: e}, &jobPlugin{name: "db"})
+	a.SetLogger(log.NewStdLogger(io.Discard))
+	a.InitLogger()
+	a.SetControlPlane(&registryControlPlane{registrar: r})
+
+	k := kratos.New(append([]kratos.Option{kratos.Name("test"), kratos.Signal(noSignal{})}, KratosOptions()...)...)
+	done := make(chan error, 1)
+	go func() {
+		done <- k.Run()
+	}()
+	select {
+	case <-r.registered:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the instance to be registered")
+	}
+	if err := k.Stop(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return e.String()
+}
+
+func TestDeregisterBeforeDrain(t *testing.T) {
+	if steps := runAndStop(t); steps != "deregister,drain" {
+		t.Errorf("Expected the instance to be deregistered before draining, but got %v", steps)
+	}
+}
+
+// TestRestartKeepsRegistration must run last, a process can't take back the listeners it handed over
+func TestRestartKeepsRegistration(t *testing.T) {
+	if _, err := app.Listen("test", "tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	pid, err := app.Restart()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p, err := os.FindProcess(pid); err == nil {
+		_, _ = p.Wait()
+	}
+	if steps := runAndStop(t); steps != "drain" {
+		t.Errorf("Expected the registration to be kept for the restarted process, but got %v", steps)
+	}
+}
@@ -0,0 +1,34 @@
+package boot
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/conf"
+	"os"
+)
+
+// validate checks the configuration given by -conf against the plugins of this binary without loading any of
+// them, it prints every problem to stderr and exits with 1 when there is one
+func (b *Boot) validate() {
+	if b.conf == nil {
+		b.loadLocalBootFile()
+	}
+	var bootConf conf.Bootstrap
+	if err := b.conf.Scan(&bootConf); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid bootstrap configuration:", err)
+		os.Exit(1)
+	}
+	app.NewApp(b.conf, b.plugins...)
+	// Only warnings and errors of the framework go to stderr, next to the problems found
+	app.Lynx().SetLogger(log.NewFilter(log.NewStdLogger(os.Stderr), log.FilterLevel(log.LevelWarn)))
+	app.Lynx().InitLogger()
+	errs := app.Lynx().PlugManager().ValidateConfig(b.conf)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("configuration is valid")
+}
@@ -0,0 +1,243 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// CmdDiff represents the doctor diff command.
+var CmdDiff = &cobra.Command{
+	Use:   "diff",
+	Short: "Show the plugin changes between two boot configurations",
+	Long: "Compare two boot configurations and print the plugins that are added, removed or reconfigured. The " +
+		"plugins are resolved through the service in the current directory to tell which changes the running " +
+		"service applies without a restart, e.g. lynx doctor diff -c old.yaml -c new.yaml",
+	RunE: runDiff,
+}
+
+var (
+	configs []string
+	diffPkg string
+)
+
+// frameworkSections are sections under lynx that configure the framework itself rather than a plugin
+var frameworkSections = map[string]bool{
+	"application":     true,
+	"plugins":         true,
+	"shutdown":        true,
+	"config_limits":   true,
+	"startup_barrier": true,
+	"control_plane":   true,
+}
+
+func init() {
+	CmdDiff.Flags().StringArrayVarP(&configs, "config", "c", nil, "boot configuration file, exactly two are required")
+	CmdDiff.Flags().StringVarP(&diffPkg, "package", "p", ".", "main package of the service")
+}
+
+func runDiff(_ *cobra.Command, _ []string) error {
+	if len(configs) != 2 {
+		return errors.New("exactly two --config files are required")
+	}
+	before, err := loadSections(configs[0])
+	if err != nil {
+		return err
+	}
+	after, err := loadSections(configs[1])
+	if err != nil {
+		return err
+	}
+
+	changes := diffSections(before, after)
+	if len(changes) == 0 {
+		fmt.Println("✅ No plugin changes")
+		return nil
+	}
+	plugins, err := resolvePlugins(configs[1])
+	if err != nil {
+		fmt.Printf("⚠️  Could not resolve the plugins of the service, assuming every change needs a restart: %v\n\n", err)
+	}
+
+	restart := false
+	for _, c := range changes {
+		// Plugins are only created at startup, adding or removing one always needs a restart
+		reloadable := c.kind == changed && plugins[c.section].Configurable
+		apply := color.RedString("restart required")
+		if reloadable {
+			apply = color.GreenString("hot reloadable")
+		} else {
+			restart = true
+		}
+		switch c.kind {
+		case added:
+			fmt.Printf("%s %s (%s)\n", color.GreenString("+ %s", c.section), "plugin added", apply)
+		case removed:
+			fmt.Printf("%s %s (%s)\n", color.RedString("- %s", c.section), "plugin removed", apply)
+		case changed:
+			fmt.Printf("%s %s (%s)\n", color.YellowString("~ %s", c.section), "plugin reconfigured", apply)
+			for _, k := range c.keys {
+				fmt.Printf("    %s: %v -> %v\n", k.path, display(k.before), display(k.after))
+			}
+		}
+	}
+	if restart {
+		fmt.Println("\n🔁 Restart the service to apply the changes marked restart required")
+	} else {
+		fmt.Println("\n♻️  The running service applies all changes on reload, no restart required")
+	}
+	return nil
+}
+
+// pluginInfo is a plugin of the service as printed by its -plugins flag
+type pluginInfo struct {
+	Name         string `json:"name"`
+	ConfPrefix   string `json:"conf_prefix"`
+	Configurable bool   `json:"configurable"`
+}
+
+// resolvePlugins runs the service with -plugins and returns its plugins for the configuration keyed by their
+// configuration prefix. Only the service knows which of its plugins apply configuration changes without a restart.
+func resolvePlugins(conf string) (map[string]pluginInfo, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", diffPkg, "-conf", conf, "-plugins")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// The plugins are the last line, the service may log before printing them
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var infos []pluginInfo
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &infos); err != nil {
+		return nil, fmt.Errorf("unexpected -plugins output: %w", err)
+	}
+	plugins := make(map[string]pluginInfo, len(infos))
+	for _, p := range infos {
+		plugins[p.ConfPrefix] = p
+	}
+	return plugins, nil
+}
+
+type changeKind int
+
+const (
+	added changeKind = iota
+	removed
+	changed
+)
+
+type keyChange struct {
+	path   string
+	before interface{}
+	after  interface{}
+}
+
+type sectionChange struct {
+	section string
+	kind    changeKind
+	keys    []keyChange
+}
+
+// loadSections reads a boot configuration and returns the sections found under the lynx key, keyed by their
+// configuration prefix
+func loadSections(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	lynx, _ := root["lynx"].(map[string]interface{})
+	sections := make(map[string]interface{})
+	for k, v := range lynx {
+		if !frameworkSections[k] {
+			sections["lynx."+k] = v
+		}
+	}
+	// The certificate plugin lives under the application section
+	if application, ok := lynx["application"].(map[string]interface{}); ok {
+		if tls, ok := application["tls"]; ok {
+			sections["lynx.application.tls"] = tls
+		}
+	}
+	return sections, nil
+}
+
+func diffSections(before, after map[string]interface{}) []sectionChange {
+	names := make(map[string]bool)
+	for k := range before {
+		names[k] = true
+	}
+	for k := range after {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []sectionChange
+	for _, name := range sorted {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		switch {
+		case !inBefore:
+			changes = append(changes, sectionChange{section: name, kind: added})
+		case !inAfter:
+			changes = append(changes, sectionChange{section: name, kind: removed})
+		default:
+			var keys []keyChange
+			diffValues(name, b, a, &keys)
+			if len(keys) > 0 {
+				changes = append(changes, sectionChange{section: name, kind: changed, keys: keys})
+			}
+		}
+	}
+	return changes
+}
+
+// diffValues walks both values and records every leaf that differs
+func diffValues(path string, before, after interface{}, keys *[]keyChange) {
+	bm, bok := before.(map[string]interface{})
+	am, aok := after.(map[string]interface{})
+	if !bok || !aok {
+		if !reflect.DeepEqual(before, after) {
+			*keys = append(*keys, keyChange{path: path, before: before, after: after})
+		}
+		return
+	}
+	names := make([]string, 0, len(bm)+len(am))
+	for k := range bm {
+		names = append(names, k)
+	}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		diffValues(path+"."+k, bm[k], am[k], keys)
+	}
+}
+
+func display(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,15 @@
+package doctor
+
+import "github.com/spf13/cobra"
+
+// CmdDoctor represents the doctor command.
+var CmdDoctor = &cobra.Command{
+	Use:   "doctor",
+	Short: "Inspect lynx service configurations",
+	Long:  "Inspect lynx service configurations and report problems or the impact of changes.",
+}
+
+func init() {
+	CmdDoctor.AddCommand(CmdDiff)
+	CmdDoctor.AddCommand(CmdGraph)
+}
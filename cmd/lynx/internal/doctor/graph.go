@@ -0,0 +1,35 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+// CmdGraph represents the doctor graph command.
+var CmdGraph = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the plugin dependency graph of a service",
+	Long: "Run the service in the current directory with -graph and print its plugin dependency graph in DOT format, " +
+		"e.g. lynx doctor graph -c ./configs | dot -Tsvg > plugins.svg",
+	RunE: runGraph,
+}
+
+var (
+	graphConf string
+	graphPkg  string
+)
+
+func init() {
+	CmdGraph.Flags().StringVarP(&graphConf, "config", "c", "./configs", "boot configuration file or directory")
+	CmdGraph.Flags().StringVarP(&graphPkg, "package", "p", ".", "main package of the service")
+}
+
+func runGraph(_ *cobra.Command, _ []string) error {
+	// The plugins are compiled into the service, so only the service itself can resolve its graph
+	cmd := exec.Command("go", "run", graphPkg, "-conf", graphConf, "-graph")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
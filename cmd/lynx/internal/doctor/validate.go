@@ -0,0 +1,36 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+// CmdValidate represents the validate command.
+var CmdValidate = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the boot configuration of a service",
+	Long: "Run the service in the current directory with -validate: the configuration is checked against the " +
+		"plugins compiled into the service without loading them. Unknown plugin sections, schema violations and " +
+		"unsatisfiable dependencies are reported and make the command exit with 1, e.g. lynx validate -c ./configs",
+	RunE: runValidate,
+}
+
+var (
+	validateConf string
+	validatePkg  string
+)
+
+func init() {
+	CmdValidate.Flags().StringVarP(&validateConf, "config", "c", "./configs", "boot configuration file or directory")
+	CmdValidate.Flags().StringVarP(&validatePkg, "package", "p", ".", "main package of the service")
+}
+
+func runValidate(_ *cobra.Command, _ []string) error {
+	// The plugins and their schemas are compiled into the service, so only the service itself can validate
+	cmd := exec.Command("go", "run", validatePkg, "-conf", validateConf, "-validate")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
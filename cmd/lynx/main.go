@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/go-lynx/lynx/cmd/lynx/internal/doctor"
 	"github.com/go-lynx/lynx/cmd/lynx/internal/project"
 	"log"
 
@@ -16,6 +17,8 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(project.CmdNew)
+	rootCmd.AddCommand(doctor.CmdDoctor)
+	rootCmd.AddCommand(doctor.CmdValidate)
 }
 
 func main() {
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"sync"
+)
+
+var (
+	// registry holds the collectors of the framework and its plugins
+	registry = prometheus.NewRegistry()
+
+	mu        sync.Mutex
+	gatherers = make(map[string]prometheus.Gatherer)
+	sqlPools  = make(map[string]prometheus.Collector)
+)
+
+// Registry returns the registry Lynx and its plugins register their collectors with
+func Registry() *prometheus.Registry {
+	return registry
+}
+
+// RegisterGatherer adds the gatherer of a plugin whose metrics are exposed next to the ones of the registry,
+// prefixed with the plugin namespace. A gatherer registered under an existing name replaces the previous one.
+func RegisterGatherer(name string, g prometheus.Gatherer) {
+	RegisterGathererWithNamespace(name, Namespace(name), g)
+}
+
+// RegisterGathererWithNamespace is RegisterGatherer with a namespace other than the plugin name
+func RegisterGathererWithNamespace(name, namespace string, g prometheus.Gatherer) {
+	mu.Lock()
+	defer mu.Unlock()
+	gatherers[name] = Prefixed(namespace, g)
+}
+
+// UnregisterGatherer removes a gatherer added with RegisterGatherer
+func UnregisterGatherer(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(gatherers, name)
+}
+
+// Gatherer returns a gatherer combining the registry and all registered gatherers
+func Gatherer() prometheus.Gatherer {
+	mu.Lock()
+	defer mu.Unlock()
+	all := prometheus.Gatherers{registry}
+	for _, g := range gatherers {
+		all = append(all, g)
+	}
+	return all
+}
+
+// Handler serves all metrics in the Prometheus exposition format,
+// e.g. http.GetServer().Handle("/metrics", metrics.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		promhttp.HandlerFor(Gatherer(), promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+// RegisterSQLPool exports the connection pool statistics of db (open, idle and in use connections, wait count
+// and wait duration) labeled with the given name. Registering a name twice replaces the previous pool.
+func RegisterSQLPool(name string, db *sql.DB) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if old, ok := sqlPools[name]; ok {
+		registry.Unregister(old)
+	}
+	c := collectors.NewDBStatsCollector(db, name)
+	if err := registry.Register(c); err != nil {
+		delete(sqlPools, name)
+		return err
+	}
+	sqlPools[name] = c
+	return nil
+}
+
+// UnregisterSQLPool stops exporting the pool statistics registered under name
+func UnregisterSQLPool(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if c, ok := sqlPools[name]; ok {
+		registry.Unregister(c)
+		delete(sqlPools, name)
+	}
+}
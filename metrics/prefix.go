@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"strings"
+)
+
+// Namespace turns a plugin name into a metric name prefix, e.g. "lynx.db" becomes "lynx_db"
+func Namespace(plugin string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, plugin)
+}
+
+// Registerer returns a registerer for the collectors of a plugin, every metric it registers is prefixed with
+// the plugin namespace so two plugins exporting requests_total don't collide
+func Registerer(plugin string) prometheus.Registerer {
+	return prometheus.WrapRegistererWithPrefix(Namespace(plugin)+"_", registry)
+}
+
+// Prefixed wraps a gatherer so the names of all gathered metrics start with namespace, metrics already
+// carrying the prefix are left as they are
+func Prefixed(namespace string, g prometheus.Gatherer) prometheus.Gatherer {
+	prefix := namespace + "_"
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		for i, mf := range families {
+			if strings.HasPrefix(mf.GetName(), prefix) {
+				continue
+			}
+			name := prefix + mf.GetName()
+			families[i] = &dto.MetricFamily{Name: &name, Help: mf.Help, Type: mf.Type, Metric: mf.Metric}
+		}
+		return families, err
+	})
+}
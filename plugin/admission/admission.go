@@ -0,0 +1,102 @@
+package admission
+
+import (
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin"
+	"github.com/go-lynx/lynx/plugin/admission/conf"
+	"sync"
+)
+
+var (
+	name       = "admission"
+	confPrefix = "lynx.admission"
+)
+
+type PlugAdmission struct {
+	queue  *queue
+	conf   *conf.Admission
+	weight int
+	// mu guards conf against reconfiguration
+	mu sync.RWMutex
+}
+
+type Option func(a *PlugAdmission)
+
+func Weight(w int) Option {
+	return func(a *PlugAdmission) {
+		a.weight = w
+	}
+}
+
+func Config(c *conf.Admission) Option {
+	return func(a *PlugAdmission) {
+		a.conf = c
+	}
+}
+
+func (a *PlugAdmission) Load(b config.Value) (plugin.Plugin, error) {
+	err := b.Scan(a.conf)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Lynx().Helper().Infof("Initializing admission control")
+	a.queue = newQueue(concurrencyLimit(a.conf), int(a.conf.GetMaxQueue()), priorityQueueLimit(a.conf),
+		a.conf.GetMaxWait().AsDuration())
+	app.Lynx().Helper().Infof("Admission control successfully initialized, max concurrency:%v max queue:%v max wait:%v",
+		a.conf.GetMaxConcurrency(), a.conf.GetMaxQueue(), a.conf.GetMaxWait().AsDuration())
+	return a, nil
+}
+
+func (a *PlugAdmission) Unload() error {
+	return nil
+}
+
+// Configure applies new limits and priority operations without dropping queued requests
+func (a *PlugAdmission) Configure(b config.Value) error {
+	c := &conf.Admission{}
+	if err := b.Scan(c); err != nil {
+		return err
+	}
+	if c.GetMaxConcurrency() < 0 || c.GetMaxQueue() < 0 || c.GetMaxPriorityQueue() < 0 || c.GetMaxWait().AsDuration() < 0 {
+		return fmt.Errorf("admission limits must not be negative")
+	}
+	a.queue.resize(concurrencyLimit(c), int(c.GetMaxQueue()), priorityQueueLimit(c), c.GetMaxWait().AsDuration())
+	a.mu.Lock()
+	a.conf = c
+	a.mu.Unlock()
+	app.Lynx().Helper().Infof("Admission control reconfigured, max concurrency:%v max queue:%v max wait:%v",
+		c.GetMaxConcurrency(), c.GetMaxQueue(), c.GetMaxWait().AsDuration())
+	return nil
+}
+
+// concurrencyLimit returns the configured concurrency limit, without an explicit limit every request is
+// admitted right away
+func concurrencyLimit(c *conf.Admission) int {
+	limit := int(c.GetMaxConcurrency())
+	if limit <= 0 {
+		limit = int(^uint(0) >> 1)
+	}
+	return limit
+}
+
+// priorityQueueLimit returns the number of priority requests allowed to wait, max_queue unless configured
+func priorityQueueLimit(c *conf.Admission) int {
+	if n := int(c.GetMaxPriorityQueue()); n > 0 {
+		return n
+	}
+	return int(c.GetMaxQueue())
+}
+
+func Admission(opts ...Option) plugin.Plugin {
+	a := &PlugAdmission{
+		weight: 800,
+		conf:   &conf.Admission{},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
@@ -0,0 +1,40 @@
+package admission
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"strings"
+)
+
+// Server returns a server middleware that queues requests once the concurrency limit is reached and sheds them
+// when the queue is full or the wait exceeds the configured maximum. Add it to a server with, for example,
+// http.GetServer().Use("/*", admission.Server()).
+func Server() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			p := GetPlugin()
+			if err := p.queue.acquire(ctx, p.isPriority(ctx)); err != nil {
+				return nil, err
+			}
+			defer p.queue.release()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// isPriority reports whether the current operation matches one of the configured priority prefixes
+func (a *PlugAdmission) isPriority(ctx context.Context) bool {
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return false
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, prefix := range a.conf.GetPriorityOperations() {
+		if strings.HasPrefix(tr.Operation(), prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package admission
+
+import (
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/factory"
+	"github.com/go-lynx/lynx/plugin"
+)
+
+func init() {
+	factory.GlobalPluginFactory().Register(name, confPrefix, func() plugin.Plugin {
+		return Admission()
+	})
+}
+
+func GetPlugin() *PlugAdmission {
+	return app.Lynx().PlugManager().GetPlugin(name).(*PlugAdmission)
+}
+
+// GetStats returns the current queue depth and admission counters
+func GetStats() Stats {
+	return GetPlugin().queue.snapshot()
+}
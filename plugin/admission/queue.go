@@ -0,0 +1,228 @@
+package admission
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sync"
+	"time"
+)
+
+// ErrOverloaded is returned when a request is shed, it maps to HTTP 429 and gRPC ResourceExhausted
+var ErrOverloaded = errors.New(429, "TOO_MANY_REQUESTS", "service is overloaded, please retry later")
+
+// Priority label values of the admission metrics
+const (
+	labelPriority = "priority"
+	labelNormal   = "normal"
+)
+
+var (
+	runningGauge = promauto.With(metrics.Registry()).NewGauge(prometheus.GaugeOpts{
+		Name: "lynx_admission_running",
+		Help: "Requests currently being handled.",
+	})
+	queuedGauge = promauto.With(metrics.Registry()).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lynx_admission_queued",
+		Help: "Requests waiting for a free slot.",
+	}, []string{"priority"})
+	shedCounter = promauto.With(metrics.Registry()).NewCounterVec(prometheus.CounterOpts{
+		Name: "lynx_admission_shed_total",
+		Help: "Requests rejected because the queue was full or the wait too long.",
+	}, []string{"priority"})
+	waitHistogram = promauto.With(metrics.Registry()).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lynx_admission_wait_seconds",
+		Help:    "Time admitted requests spent waiting in the queue.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"priority"})
+)
+
+// Stats is a snapshot of the admission queue
+type Stats struct {
+	// Running is the number of requests currently being handled
+	Running int
+	// Queued is the number of requests waiting for a slot
+	Queued int
+	// Admitted is the total number of admitted requests
+	Admitted uint64
+	// Shed is the total number of rejected requests
+	Shed uint64
+	// TotalWait is the accumulated time admitted requests spent in the queue
+	TotalWait time.Duration
+}
+
+// queue admits up to limit requests at a time and keeps a bounded number of requests waiting, priority
+// requests are woken before normal ones and bounded by their own limit
+type queue struct {
+	mu          sync.Mutex
+	limit       int
+	maxQueue    int
+	maxPriority int
+	maxWait     time.Duration
+	high        []chan struct{}
+	low         []chan struct{}
+	stats       Stats
+}
+
+func newQueue(limit, maxQueue, maxPriority int, maxWait time.Duration) *queue {
+	return &queue{
+		limit:       limit,
+		maxQueue:    maxQueue,
+		maxPriority: maxPriority,
+		maxWait:     maxWait,
+	}
+}
+
+func priorityLabel(priority bool) string {
+	if priority {
+		return labelPriority
+	}
+	return labelNormal
+}
+
+// observe publishes the queue state, called with mu held
+func (q *queue) observe() {
+	runningGauge.Set(float64(q.stats.Running))
+	queuedGauge.WithLabelValues(labelPriority).Set(float64(len(q.high)))
+	queuedGauge.WithLabelValues(labelNormal).Set(float64(len(q.low)))
+}
+
+// acquire waits for a free slot, it fails with ErrOverloaded when the queue is full or the wait is longer than
+// maxWait. A zero maxWait waits until a slot frees up or ctx is done, which fails with the error of ctx.
+func (q *queue) acquire(ctx context.Context, priority bool) error {
+	label := priorityLabel(priority)
+	q.mu.Lock()
+	if q.stats.Running < q.limit {
+		q.stats.Running++
+		q.stats.Admitted++
+		q.observe()
+		q.mu.Unlock()
+		waitHistogram.WithLabelValues(label).Observe(0)
+		return nil
+	}
+	// Priority requests only compete with each other for room in the queue
+	full := q.stats.Queued >= q.maxQueue
+	if priority {
+		full = len(q.high) >= q.maxPriority
+	}
+	if full {
+		q.stats.Shed++
+		q.mu.Unlock()
+		shedCounter.WithLabelValues(label).Inc()
+		return ErrOverloaded
+	}
+	w := make(chan struct{})
+	if priority {
+		q.high = append(q.high, w)
+	} else {
+		q.low = append(q.low, w)
+	}
+	q.stats.Queued++
+	q.observe()
+	maxWait := q.maxWait
+	q.mu.Unlock()
+
+	start := time.Now()
+	var expired <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-w:
+		wait := time.Since(start)
+		q.mu.Lock()
+		q.stats.TotalWait += wait
+		q.mu.Unlock()
+		waitHistogram.WithLabelValues(label).Observe(wait.Seconds())
+		return nil
+	case <-expired:
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.remove(w) {
+		// The slot was handed over while giving up, keep it
+		wait := time.Since(start)
+		q.stats.TotalWait += wait
+		waitHistogram.WithLabelValues(label).Observe(wait.Seconds())
+		return nil
+	}
+	q.stats.Queued--
+	q.stats.Shed++
+	q.observe()
+	shedCounter.WithLabelValues(label).Inc()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ErrOverloaded
+}
+
+// release frees a slot, handing it over to the next waiting request if there is one
+func (q *queue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var w chan struct{}
+	if len(q.high) > 0 {
+		w, q.high = q.high[0], q.high[1:]
+	} else if len(q.low) > 0 {
+		w, q.low = q.low[0], q.low[1:]
+	}
+	if w == nil {
+		q.stats.Running--
+		q.observe()
+		return
+	}
+	q.stats.Queued--
+	q.stats.Admitted++
+	q.observe()
+	close(w)
+}
+
+// resize applies new limits, waiting requests are admitted right away when the concurrency limit grows
+func (q *queue) resize(limit, maxQueue, maxPriority int, maxWait time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.observe()
+	q.limit = limit
+	q.maxQueue = maxQueue
+	q.maxPriority = maxPriority
+	q.maxWait = maxWait
+	for q.stats.Running < q.limit {
+		var w chan struct{}
+		if len(q.high) > 0 {
+			w, q.high = q.high[0], q.high[1:]
+		} else if len(q.low) > 0 {
+			w, q.low = q.low[0], q.low[1:]
+		} else {
+			return
+		}
+		q.stats.Running++
+		q.stats.Queued--
+		q.stats.Admitted++
+		close(w)
+	}
+}
+
+// remove drops a waiter from the queue, it reports false when the waiter was already woken
+func (q *queue) remove(w chan struct{}) bool {
+	for _, list := range []*[]chan struct{}{&q.high, &q.low} {
+		for i, c := range *list {
+			if c == w {
+				*list = append((*list)[:i], (*list)[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (q *queue) snapshot() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
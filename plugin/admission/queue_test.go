@@ -0,0 +1,137 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n requests are queued
+func waitQueued(t *testing.T, q *queue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for q.snapshot().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %v queued requests, but got %v", n, q.snapshot().Queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// acquireAsync acquires a slot in the background and returns the result
+func acquireAsync(ctx context.Context, q *queue, priority bool) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- q.acquire(ctx, priority)
+	}()
+	return result
+}
+
+func TestQueueFull(t *testing.T) {
+	q := newQueue(1, 1, 1, 0)
+	if err := q.acquire(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	normal := acquireAsync(context.Background(), q, false)
+	waitQueued(t, q, 1)
+	if err := q.acquire(context.Background(), false); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Expected a normal request beyond max_queue to be shed, but got %v", err)
+	}
+
+	// Priority requests have room of their own
+	priority := acquireAsync(context.Background(), q, true)
+	waitQueued(t, q, 2)
+	if err := q.acquire(context.Background(), true); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Expected a priority request beyond max_priority_queue to be shed, but got %v", err)
+	}
+
+	// The priority request is admitted first
+	q.release()
+	if err := <-priority; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case err := <-normal:
+		t.Fatalf("Expected the normal request to keep waiting, but got %v", err)
+	default:
+	}
+	q.release()
+	if err := <-normal; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.release()
+	if s := q.snapshot(); s.Running != 0 || s.Queued != 0 || s.Admitted != 3 || s.Shed != 2 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestQueueMaxWait(t *testing.T) {
+	q := newQueue(1, 1, 1, 20*time.Millisecond)
+	if err := q.acquire(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	st := time.Now()
+	if err := q.acquire(context.Background(), false); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Expected the request to be shed after max_wait, but got %v", err)
+	}
+	if waited := time.Since(st); waited < 20*time.Millisecond {
+		t.Errorf("Expected the request to wait max_wait, but it waited %v", waited)
+	}
+	if s := q.snapshot(); s.Running != 1 || s.Queued != 0 || s.Shed != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestQueueCancel(t *testing.T) {
+	// Without max_wait a request waits until it is cancelled
+	q := newQueue(1, 1, 1, 0)
+	if err := q.acquire(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	result := acquireAsync(ctx, q, false)
+	waitQueued(t, q, 1)
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation error, but got %v", err)
+	}
+	// The cancelled request doesn't take the next free slot
+	q.release()
+	if s := q.snapshot(); s.Running != 0 || s.Queued != 0 || s.Shed != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestQueueResize(t *testing.T) {
+	q := newQueue(1, 2, 2, 0)
+	if err := q.acquire(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first := acquireAsync(context.Background(), q, false)
+	second := acquireAsync(context.Background(), q, true)
+	waitQueued(t, q, 2)
+
+	// A larger limit admits the waiting requests right away
+	q.resize(3, 2, 2, 0)
+	for _, result := range []<-chan error{first, second} {
+		if err := <-result; err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if s := q.snapshot(); s.Running != 3 || s.Queued != 0 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+
+	// A smaller limit lets the running requests finish and only admits new ones below it
+	q.resize(1, 0, 0, 0)
+	q.release()
+	if err := q.acquire(context.Background(), false); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Expected the request to be shed above the new limit, but got %v", err)
+	}
+	q.release()
+	q.release()
+	if err := q.acquire(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package admission
+
+import "github.com/go-kratos/kratos/v2/config"
+
+func (a *PlugAdmission) Name() string {
+	return name
+}
+
+func (a *PlugAdmission) DependsOn(config.Value) []string {
+	return nil
+}
+
+func (a *PlugAdmission) ConfPrefix() string {
+	return confPrefix
+}
+
+func (a *PlugAdmission) Weight() int {
+	return a.weight
+}
+
+func (a *PlugAdmission) ConfigSchema() []byte {
+	return []byte(`{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "max_concurrency": {"type": "integer"},
+    "max_queue": {"type": "integer"},
+    "max_priority_queue": {"type": "integer"},
+    "max_wait": {"type": "string"},
+    "priority_operations": {"type": "array", "items": {"type": "string"}}
+  }
+}`)
+}
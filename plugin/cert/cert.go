@@ -1,12 +1,15 @@
 package cert
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	_ "database/sql"
 	"entgo.io/ent/dialect/sql"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-lynx/lynx/app"
 	"github.com/go-lynx/lynx/plugin"
 	"github.com/go-lynx/lynx/plugin/cert/conf"
+	"sync"
 )
 
 var (
@@ -19,20 +22,41 @@ type PlugCert struct {
 	tls    *conf.Tls
 	cert   *conf.Cert
 	weight int
+	// mu guards cert and tlsCert, which are swapped together when the certificate is rotated
+	mu      sync.RWMutex
+	tlsCert *tls.Certificate
+	// source is the watched certificate configuration
+	source config.Config
+	// clientCAs and verify configure mutual TLS, see WithClientCAs and WithVerifyPeerCertificate
+	clientCAs *x509.CertPool
+	verify    func(rawCerts [][]byte, chains [][]*x509.Certificate) error
 }
 
 func (ce *PlugCert) GetCrt() []byte {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
 	return []byte(ce.cert.GetCrt())
 }
 
 func (ce *PlugCert) GetKey() []byte {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
 	return []byte(ce.cert.GetKey())
 }
 
 func (ce *PlugCert) GetRootCA() []byte {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
 	return []byte(ce.cert.GetRootCA())
 }
 
+// TLSCertificate returns the current parsed certificate, see app.GetCertificate
+func (ce *PlugCert) TLSCertificate() *tls.Certificate {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+	return ce.tlsCert
+}
+
 type Option func(ce *PlugCert)
 
 func Weight(w int) Option {
@@ -52,31 +76,88 @@ func (ce *PlugCert) Load(b config.Value) (plugin.Plugin, error) {
 	if err != nil {
 		return nil, err
 	}
-	app.Lynx().Helper().Infof("Application Certificate Loading")
+	app.Lynx().PluginHelper(name).Infof("Application Certificate Loading")
 
-	source, err := app.Lynx().ControlPlane().Config(ce.tls.GetFileName(), ce.tls.GetGroup())
+	c, err := ce.open()
 	if err != nil {
 		return nil, err
 	}
-	c := config.New(config.WithSource(source))
-	if err := c.Load(); err != nil {
+	if err := ce.apply(c); err != nil {
+		_ = c.Close()
 		return nil, err
 	}
 
-	err = c.Scan(ce.cert)
-	if err != nil {
-		return nil, err
+	// 证书文件变化时自动轮换，新的 TLS 握手使用新证书，已建立的连接保持原证书
+	ce.source = c
+	for _, key := range []string{"crt", "key", "rootCA"} {
+		if err := c.Watch(key, ce.rotate); err != nil {
+			app.Lynx().PluginHelper(name).Warnf("Application certificate is not watched for %v changes: %v", key, err)
+		}
 	}
 
 	app.Lynx().SetCert(ce)
-	app.Lynx().Helper().Infof("Application Certificate Loaded successfully")
+	app.Lynx().PluginHelper(name).Infof("Application Certificate Loaded successfully")
 	return ce, nil
 }
 
 func (ce *PlugCert) Unload() error {
+	if ce.source == nil {
+		return nil
+	}
+	return ce.source.Close()
+}
+
+// Reload reads the certificate from its source again and swaps it in place, the current certificate is kept when
+// the new one can't be read or doesn't parse
+func (ce *PlugCert) Reload() error {
+	c, err := ce.open()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return ce.apply(c)
+}
+
+// open loads the certificate configuration from the control plane
+func (ce *PlugCert) open() (config.Config, error) {
+	source, err := app.Lynx().ControlPlane().Config(ce.tls.GetFileName(), ce.tls.GetGroup())
+	if err != nil {
+		return nil, err
+	}
+	c := config.New(config.WithSource(source))
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// apply parses the certificate of c and swaps it with the current one
+func (ce *PlugCert) apply(c config.Config) error {
+	next := &conf.Cert{}
+	if err := c.Scan(next); err != nil {
+		return err
+	}
+	tlsCert, err := tls.X509KeyPair([]byte(next.GetCrt()), []byte(next.GetKey()))
+	if err != nil {
+		return err
+	}
+	ce.mu.Lock()
+	ce.cert = next
+	ce.tlsCert = &tlsCert
+	ce.mu.Unlock()
 	return nil
 }
 
+// rotate applies a changed certificate, a certificate and key that don't match yet, e.g. while the files are
+// replaced one after another, keep the current certificate until the next change
+func (ce *PlugCert) rotate(string, config.Value) {
+	if err := ce.apply(ce.source); err != nil {
+		app.Lynx().PluginHelper(name).Errorf("Application certificate rotation failed, keeping the current certificate: %v", err)
+		return
+	}
+	app.Lynx().PluginHelper(name).Infof("Application certificate rotated")
+}
+
 func Cert(opts ...Option) plugin.Plugin {
 	c := &PlugCert{
 		weight: 100,
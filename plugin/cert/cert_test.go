@@ -0,0 +1,77 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/app"
+	"testing"
+)
+
+// certConfig is the certificate configuration of a certificate and its root CA
+func certConfig(t *testing.T, cert, ca *tls.Certificate) config.Config {
+	t.Helper()
+	key, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]string{
+		"crt":    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})),
+		"key":    string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key})),
+		"rootCA": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate[0]})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := config.New(config.WithSource(app.NewStaticSource("cert", data, "json")))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+	})
+	return c
+}
+
+func TestRootCARotation(t *testing.T) {
+	ca1, ca2 := issue(t, "ca-1", nil), issue(t, "ca-2", nil)
+	ce := Cert().(*PlugCert)
+	newTestApp(t, ce)
+	if err := ce.apply(certConfig(t, issue(t, "server", ca1), ca1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	serverConf, err := app.ServerTLSConfig(tls.RequireAndVerifyClientCert)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientConf := func(ca *tls.Certificate) *tls.Config {
+		// The client follows the root CA of the application as well
+		return &tls.Config{
+			ServerName:         "server",
+			InsecureSkipVerify: true,
+			VerifyConnection:   app.VerifyServerCertificate,
+			Certificates:       []tls.Certificate{*issue(t, "client", ca)},
+		}
+	}
+	if err := handshake(serverConf, clientConf(ca1)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := handshake(serverConf, clientConf(ca2)); err == nil {
+		t.Error("Expected a client certificate of another CA to be rejected")
+	}
+
+	// Rotating the root CA applies to new handshakes of the same configuration
+	if err := ce.apply(certConfig(t, issue(t, "server", ca2), ca2)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := handshake(serverConf, clientConf(ca2)); err != nil {
+		t.Errorf("Expected the rotated root CA to be trusted, but got %v", err)
+	}
+	if err := handshake(serverConf, clientConf(ca1)); err == nil {
+		t.Error("Expected a client certificate of the previous CA to be rejected")
+	}
+}
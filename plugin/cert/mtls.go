@@ -0,0 +1,84 @@
+package cert
+
+import (
+	"crypto/x509"
+	"fmt"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strings"
+)
+
+var mtlsRejections = promauto.With(metrics.Registry()).NewCounter(prometheus.CounterOpts{
+	Name: "lynx_mtls_rejections_total",
+	Help: "Client certificates rejected by the identity allowlist.",
+})
+
+// WithClientCAs verifies client certificates against pool instead of the root CA of the certificate configuration
+func WithClientCAs(pool *x509.CertPool) Option {
+	return func(ce *PlugCert) {
+		ce.clientCAs = pool
+	}
+}
+
+// WithVerifyPeerCertificate adds a check run on every client certificate after the identity allowlist
+func WithVerifyPeerCertificate(fn func(rawCerts [][]byte, chains [][]*x509.Certificate) error) Option {
+	return func(ce *PlugCert) {
+		ce.verify = fn
+	}
+}
+
+// ClientCAs returns the pool set with WithClientCAs, nil when the root CA of the certificate configuration is used
+func (ce *PlugCert) ClientCAs() *x509.CertPool {
+	return ce.clientCAs
+}
+
+// RequiresClientCert reports whether an identity allowlist is configured, which only holds for client certificates
+// verified against the CA pool
+func (ce *PlugCert) RequiresClientCert() bool {
+	return len(ce.tls.GetAllowedIdentities()) > 0
+}
+
+// VerifyPeerCertificate rejects client certificates whose identity is not in the allowlist and runs the check set
+// with WithVerifyPeerCertificate. It is nil when neither is configured, leaving one-way TLS untouched.
+func (ce *PlugCert) VerifyPeerCertificate() func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+	allowed := ce.tls.GetAllowedIdentities()
+	if len(allowed) == 0 && ce.verify == nil {
+		return nil
+	}
+	return func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+		if len(allowed) > 0 {
+			if err := checkIdentity(chains, allowed); err != nil {
+				mtlsRejections.Inc()
+				app.Lynx().PluginHelper(name).Warnf("Rejected client certificate: %v", err)
+				return err
+			}
+		}
+		if ce.verify != nil {
+			return ce.verify(rawCerts, chains)
+		}
+		return nil
+	}
+}
+
+// checkIdentity accepts the verified leaf certificate when one of its URI or DNS SANs is in the allowlist. The raw
+// certificates the client presented are never trusted, without a verified chain the handshake is rejected.
+func checkIdentity(chains [][]*x509.Certificate, allowed []string) error {
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return fmt.Errorf("no verified client certificate presented")
+	}
+	leaf := chains[0][0]
+	identities := append([]string(nil), leaf.DNSNames...)
+	for _, u := range leaf.URIs {
+		identities = append(identities, u.String())
+	}
+	for _, id := range identities {
+		for _, a := range allowed {
+			if id == a || strings.HasSuffix(a, "*") && strings.HasPrefix(id, strings.TrimSuffix(a, "*")) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("identity of %v not allowed, presented %v", leaf.Subject, identities)
+}
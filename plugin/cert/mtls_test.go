@@ -0,0 +1,149 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin/cert/conf"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// issue creates a certificate for the DNS name, signed by parent or self-signed when parent is nil
+func issue(t *testing.T, dnsName string, parent *tls.Certificate) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	signer, signerKey := template, interface{}(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// newTestApp makes a Lynx application with the certificate the global one for the duration of a test
+func newTestApp(t *testing.T, ce *PlugCert) {
+	t.Helper()
+	data := []byte(`{"lynx": {"application": {"name": "test", "version": "v1", "close_banner": true}}}`)
+	c := config.New(config.WithSource(app.NewStaticSource("test", data, "json")))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+	})
+	a := app.NewApp(c)
+	a.SetLogger(log.NewStdLogger(io.Discard))
+	a.InitLogger()
+	a.SetCert(ce)
+}
+
+func TestVerifyPeerCertificate(t *testing.T) {
+	ca := issue(t, "ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	ce := &PlugCert{tls: &conf.Tls{AllowedIdentities: []string{"orders.svc"}}, clientCAs: pool}
+	newTestApp(t, ce)
+	verify := ce.VerifyPeerCertificate()
+
+	trusted := issue(t, "orders.svc", ca)
+	other := issue(t, "payments.svc", ca)
+	untrusted := issue(t, "orders.svc", nil)
+	tests := []struct {
+		name   string
+		cert   *tls.Certificate
+		chains [][]*x509.Certificate
+		ok     bool
+	}{
+		{"verified allowed identity", trusted, [][]*x509.Certificate{{trusted.Leaf, ca.Leaf}}, true},
+		{"verified other identity", other, [][]*x509.Certificate{{other.Leaf, ca.Leaf}}, false},
+		{"unverified allowed identity", untrusted, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verify(tt.cert.Certificate, tt.chains)
+			if tt.ok && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("Expected the client certificate to be rejected")
+			}
+		})
+	}
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	ca := issue(t, "ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	ce := &PlugCert{tls: &conf.Tls{AllowedIdentities: []string{"orders.svc"}}, clientCAs: pool}
+	newTestApp(t, ce)
+
+	server := issue(t, "server", ca)
+	// The allowlist enforces verified client certificates even though any certificate is requested
+	serverConf := app.MutualTLS(&tls.Config{
+		Certificates: []tls.Certificate{*server},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	if serverConf.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("Expected %v, but got %v", tls.RequireAndVerifyClientCert, serverConf.ClientAuth)
+	}
+
+	clientConf := func(client *tls.Certificate) *tls.Config {
+		return &tls.Config{RootCAs: pool, ServerName: "server", Certificates: []tls.Certificate{*client}}
+	}
+	if err := handshake(serverConf, clientConf(issue(t, "orders.svc", ca))); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := handshake(serverConf, clientConf(issue(t, "orders.svc", nil))); err == nil {
+		t.Error("Expected a self-signed client certificate with an allowed identity to be rejected")
+	}
+}
+
+// handshake runs a TLS handshake between a server and a client and returns the error of the server side
+func handshake(serverConf, clientConf *tls.Config) error {
+	c, s := net.Pipe()
+	defer s.Close()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- tls.Server(s, serverConf).Handshake()
+	}()
+	clientErr := tls.Client(c, clientConf).Handshake()
+	// The server may still be sending its alert
+	c.Close()
+	if err := <-errs; err != nil {
+		return err
+	}
+	return clientErr
+}
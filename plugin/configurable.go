@@ -0,0 +1,9 @@
+package plugin
+
+import "github.com/go-kratos/kratos/v2/config"
+
+// Configurable is implemented by plugins that can apply a new configuration while loaded. Configure receives the
+// value under the plugin's ConfPrefix and must leave the plugin unchanged when it rejects the configuration.
+type Configurable interface {
+	Configure(c config.Value) error
+}
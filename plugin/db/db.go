@@ -6,6 +6,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/metrics"
 	"github.com/go-lynx/lynx/plugin"
 	"github.com/go-lynx/lynx/plugin/db/conf"
 	"time"
@@ -42,14 +43,14 @@ func (db *PlugDB) Load(b config.Value) (plugin.Plugin, error) {
 		return nil, err
 	}
 
-	app.Lynx().Helper().Infof("Initializing database")
+	app.Lynx().PluginHelper(name).Infof("Initializing database")
 	drv, err := sql.Open(
 		db.conf.Driver,
 		db.conf.Source,
 	)
 
 	if err != nil {
-		app.Lynx().Helper().Errorf("failed opening connection to dataBase: %v", err)
+		app.Lynx().PluginHelper(name).Errorf("failed opening connection to dataBase: %v", err)
 		panic(err)
 	}
 
@@ -65,7 +66,11 @@ func (db *PlugDB) Load(b config.Value) (plugin.Plugin, error) {
 	}
 
 	db.dri = drv
-	app.Lynx().Helper().Infof("Database successfully initialized")
+	// Export the connection pool statistics under the plugin name
+	if err := metrics.RegisterSQLPool(name, drv.DB()); err != nil {
+		app.Lynx().PluginHelper(name).Warnf("failed to register database pool metrics: %v", err)
+	}
+	app.Lynx().PluginHelper(name).Infof("Database successfully initialized")
 	return db, nil
 }
 
@@ -73,11 +78,12 @@ func (db *PlugDB) Unload() error {
 	if db.dri == nil {
 		return nil
 	}
+	metrics.UnregisterSQLPool(name)
 	if err := db.dri.Close(); err != nil {
-		app.Lynx().Helper().Error(err)
+		app.Lynx().PluginHelper(name).Error(err)
 		return err
 	}
-	app.Lynx().Helper().Info("message", "Closing the DataBase resources")
+	app.Lynx().PluginHelper(name).Info("message", "Closing the DataBase resources")
 	return nil
 }
 
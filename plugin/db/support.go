@@ -1,6 +1,11 @@
 package db
 
-import "github.com/go-kratos/kratos/v2/config"
+import (
+	"context"
+	"database/sql"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+)
 
 func (db *PlugDB) Name() string {
 	return name
@@ -17,3 +22,44 @@ func (db *PlugDB) Weight() int {
 func (db *PlugDB) ConfPrefix() string {
 	return confPrefix
 }
+
+func (db *PlugDB) ShutdownPhase() string {
+	return plugin.PhaseStores
+}
+
+func (db *PlugDB) ReadinessDependencies() []plugin.ReadinessDependency {
+	return []plugin.ReadinessDependency{
+		{
+			Name: "db:" + db.conf.GetDriver(),
+			Check: func(ctx context.Context) error {
+				return db.dri.DB().PingContext(ctx)
+			},
+		},
+	}
+}
+
+// PreWarm opens n connections and returns them to the pool, at most max_conn. Connections beyond min_conn
+// are closed again by the pool, so n should not exceed min_conn.
+func (db *PlugDB) PreWarm(ctx context.Context, n int) error {
+	if limit := db.dri.DB().Stats().MaxOpenConnections; limit > 0 && n > limit {
+		n = limit
+	}
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, c := range conns {
+			_ = c.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		c, err := db.dri.DB().Conn(ctx)
+		if err != nil {
+			return err
+		}
+		conns = append(conns, c)
+	}
+	return nil
+}
+
+func (db *PlugDB) CheckHealth(ctx context.Context) error {
+	return db.dri.DB().PingContext(ctx)
+}
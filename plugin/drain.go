@@ -0,0 +1,10 @@
+package plugin
+
+import "context"
+
+// Drainable is implemented by server plugins. Drain stops accepting new connections and waits for the active
+// requests to finish until ctx is done, it is called for all plugins once the instance is deregistered from service
+// discovery and before the Kratos application stops its servers.
+type Drainable interface {
+	Drain(ctx context.Context) error
+}
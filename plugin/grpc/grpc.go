@@ -22,6 +22,8 @@ type ServiceGrpc struct {
 	grpc   *grpc.Server
 	conf   *conf.Grpc
 	weight int
+	// named holds the additional servers configured under servers, by name
+	named map[string]*grpc.Server
 }
 
 type Option func(g *ServiceGrpc)
@@ -46,8 +48,29 @@ func (g *ServiceGrpc) Load(b config.Value) (plugin.Plugin, error) {
 	}
 
 	// 打印初始化 gRPC 服务的日志
-	app.Lynx().Helper().Infof("Initializing GRPC service")
+	app.Lynx().PluginHelper(name).Infof("Initializing GRPC service")
 
+	// 创建默认服务器
+	g.grpc, err = g.newServer(name, g.conf)
+	if err != nil {
+		return nil, err
+	}
+	// 创建配置中的具名服务器，例如单独端口上的内部管理接口
+	g.named = make(map[string]*grpc.Server, len(g.conf.GetServers()))
+	for n, c := range g.conf.GetServers() {
+		s, err := g.newServer(name+"."+n, c)
+		if err != nil {
+			return nil, err
+		}
+		g.named[n] = s
+	}
+	// 打印 gRPC 服务初始化成功的日志
+	app.Lynx().PluginHelper(name).Infof("GRPC service successfully initialized")
+	return g, nil
+}
+
+// newServer creates a server from its configuration, listener names the listener handed over on a graceful restart
+func (g *ServiceGrpc) newServer(listener string, c *conf.Grpc) (*grpc.Server, error) {
 	// 创建一个切片，用于存储 gRPC 服务器的选项
 	opts := []grpc.ServerOption{
 		// 使用 tracing 中间件，设置追踪器名称为应用程序名称
@@ -69,27 +92,31 @@ func (g *ServiceGrpc) Load(b config.Value) (plugin.Plugin, error) {
 	}
 
 	// 如果配置了网络类型，则添加到选项中
-	if g.conf.Network != "" {
-		opts = append(opts, grpc.Network(g.conf.Network))
+	if c.Network != "" {
+		opts = append(opts, grpc.Network(c.Network))
 	}
 	// 如果配置了地址，则添加到选项中
-	if g.conf.Addr != "" {
-		opts = append(opts, grpc.Address(g.conf.Addr))
+	if c.Addr != "" {
+		opts = append(opts, grpc.Address(c.Addr))
 	}
 	// 如果配置了超时时间，则添加到选项中
-	if g.conf.Timeout != nil {
-		opts = append(opts, grpc.Timeout(g.conf.Timeout.AsDuration()))
+	if c.Timeout != nil {
+		opts = append(opts, grpc.Timeout(c.Timeout.AsDuration()))
 	}
 	// 如果配置了 TLS，则加载 TLS 配置并添加到选项中
-	if g.conf.GetTls() {
-		opts = append(opts, g.tlsLoad())
+	if c.GetTls() {
+		opts = append(opts, g.tlsLoad(c))
 	}
 
+	// 通过 Lynx 获取监听器，优雅重启时沿用上一个进程的监听器
+	lis, err := app.Listen(listener, c.Network, c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, grpc.Listener(lis))
+
 	// 创建一个新的 gRPC 服务器实例
-	g.grpc = grpc.NewServer(opts...)
-	// 打印 gRPC 服务初始化成功的日志
-	app.Lynx().Helper().Infof("GRPC service successfully initialized")
-	return g, nil
+	return grpc.NewServer(opts...), nil
 }
 
 // Unload 方法用于停止并关闭 gRPC 服务器。
@@ -101,11 +128,17 @@ func (g *ServiceGrpc) Unload() error {
 	// 调用 gRPC 服务器的 Stop 方法来停止服务器，并传入一个 nil 参数。
 	// 如果 Stop 方法返回错误，则记录错误信息。
 	if err := g.grpc.Stop(nil); err != nil {
-		// 使用 app.Lynx().Helper() 记录错误信息。
-		app.Lynx().Helper().Error(err)
+		// 使用 app.Lynx().PluginHelper(name) 记录错误信息。
+		app.Lynx().PluginHelper(name).Error(err)
+	}
+	// 停止所有具名服务器
+	for n, s := range g.named {
+		if err := s.Stop(nil); err != nil {
+			app.Lynx().PluginHelper(name).Errorf("failed to stop grpc server %v: %v", n, err)
+		}
 	}
 	// 记录一条信息，指示 gRPC 资源正在被关闭。
-	app.Lynx().Helper().Info("message", "Closing the GRPC resources")
+	app.Lynx().PluginHelper(name).Info("message", "Closing the GRPC resources")
 	// 返回 nil，表示卸载过程成功，没有发生错误。
 	return nil
 }
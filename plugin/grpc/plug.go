@@ -16,3 +16,9 @@ func init() {
 func GetServer() *grpc.Server {
 	return app.Lynx().PlugManager().GetPlugin(name).(*ServiceGrpc).grpc
 }
+
+// GetNamedServer returns the server configured under servers with the given name, or nil when there is none.
+// Add server specific middleware with Use, e.g. GetNamedServer("admin").Use("/*", auth.Server()).
+func GetNamedServer(n string) *grpc.Server {
+	return app.Lynx().PlugManager().GetPlugin(name).(*ServiceGrpc).named[n]
+}
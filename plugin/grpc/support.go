@@ -1,9 +1,14 @@
 package grpc
 
 import (
+	"context"
 	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-lynx/lynx/plugin"
 	"github.com/go-lynx/lynx/plugin/cert"
 	"github.com/go-lynx/lynx/plugin/grpc/conf"
+	"sort"
 )
 
 func (g *ServiceGrpc) Weight() int {
@@ -27,9 +32,60 @@ func (g *ServiceGrpc) DependsOn(b config.Value) []string {
 	if c.Tls {
 		return []string{cert.GetName()}
 	}
+	for _, s := range c.GetServers() {
+		if s.GetTls() {
+			return []string{cert.GetName()}
+		}
+	}
 	return nil
 }
 
 func (g *ServiceGrpc) ConfPrefix() string {
 	return confPrefix
 }
+
+func (g *ServiceGrpc) ShutdownPhase() string {
+	return plugin.PhaseIngress
+}
+
+func (g *ServiceGrpc) Servers() []transport.Server {
+	servers := []transport.Server{g.grpc}
+	names := make([]string, 0, len(g.named))
+	for n := range g.named {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		servers = append(servers, g.named[n])
+	}
+	return servers
+}
+
+// Drain stops accepting connections and waits for the active calls of all servers to finish, calls still active
+// when ctx is done are cancelled
+func (g *ServiceGrpc) Drain(ctx context.Context) error {
+	if g.grpc == nil {
+		return nil
+	}
+	servers := []*grpc.Server{g.grpc}
+	for _, s := range g.named {
+		servers = append(servers, s)
+	}
+	done := make(chan struct{})
+	go func() {
+		for _, s := range servers {
+			s.GracefulStop()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, s := range servers {
+			s.Server.Stop()
+		}
+		<-done
+		return ctx.Err()
+	}
+}
@@ -2,25 +2,16 @@ package grpc
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin/grpc/conf"
 )
 
-func (g *ServiceGrpc) tlsLoad() grpc.ServerOption {
-	tlsCert, err := tls.X509KeyPair(app.Lynx().Cert().GetCrt(), app.Lynx().Cert().GetKey())
+func (g *ServiceGrpc) tlsLoad(c *conf.Grpc) grpc.ServerOption {
+	// 启动时校验证书，之后每次握手都读取当前证书与根证书，证书轮换无需重启
+	tlsConf, err := app.ServerTLSConfig(tls.ClientAuthType(c.GetTlsAuthType()), "h2")
 	if err != nil {
 		panic(err)
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(app.Lynx().Cert().GetRootCA()) {
-		panic(err)
-	}
-
-	return grpc.TLSConfig(&tls.Config{
-		Certificates: []tls.Certificate{tlsCert},
-		ClientCAs:    certPool,
-		ServerName:   app.Name(),
-		ClientAuth:   tls.ClientAuthType(g.conf.GetTlsAuthType()),
-	})
+	return grpc.TLSConfig(tlsConf)
 }
@@ -0,0 +1,42 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+)
+
+// Health states, from best to worst
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// HealthChecker is implemented by plugins that can check their own health. A nil error means healthy, an error
+// wrapped with Degraded means degraded and any other error unhealthy.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// Degraded marks a health check error as degraded, the plugin still works but with reduced capacity or quality
+func Degraded(err error) error {
+	return &degradedError{err: err}
+}
+
+// IsDegraded reports whether err was marked with Degraded
+func IsDegraded(err error) bool {
+	var d *degradedError
+	return errors.As(err, &d)
+}
@@ -22,6 +22,8 @@ type ServiceHttp struct {
 	http   *http.Server
 	conf   *conf.Http
 	weight int
+	// named holds the additional servers configured under servers, by name
+	named map[string]*http.Server
 }
 
 type Option func(h *ServiceHttp)
@@ -47,8 +49,30 @@ func (h *ServiceHttp) Load(b config.Value) (plugin.Plugin, error) {
 	}
 
 	// 使用 Lynx 应用的 Helper 记录 HTTP 服务初始化的信息。
-	app.Lynx().Helper().Infof("Initializing HTTP service")
+	app.Lynx().PluginHelper(name).Infof("Initializing HTTP service")
 
+	// 创建默认服务器
+	h.http, err = h.newServer(name, h.conf)
+	if err != nil {
+		return nil, err
+	}
+	// 创建配置中的具名服务器，例如单独端口上的内部管理接口
+	h.named = make(map[string]*http.Server, len(h.conf.GetServers()))
+	for n, c := range h.conf.GetServers() {
+		s, err := h.newServer(name+"."+n, c)
+		if err != nil {
+			return nil, err
+		}
+		h.named[n] = s
+	}
+	// 使用 Lynx 应用的 Helper 记录 HTTP 服务初始化成功的信息。
+	app.Lynx().PluginHelper(name).Infof("HTTP service successfully initialized")
+	// 返回 HTTP 服务实例和 nil 错误，表示加载成功。
+	return h, nil
+}
+
+// newServer creates a server from its configuration, listener names the listener handed over on a graceful restart
+func (h *ServiceHttp) newServer(listener string, c *conf.Http) (*http.Server, error) {
 	// 定义一个 HTTP 服务器选项切片，用于配置 HTTP 服务器。
 	var opts = []http.ServerOption{
 		// 使用中间件进行追踪，设置追踪器名称为应用名称。
@@ -74,28 +98,31 @@ func (h *ServiceHttp) Load(b config.Value) (plugin.Plugin, error) {
 	}
 
 	// 如果配置中指定了网络类型，则将其添加到 HTTP 服务器选项中。
-	if h.conf.Network != "" {
-		opts = append(opts, http.Network(h.conf.Network))
+	if c.Network != "" {
+		opts = append(opts, http.Network(c.Network))
 	}
 	// 如果配置中指定了地址，则将其添加到 HTTP 服务器选项中。
-	if h.conf.Addr != "" {
-		opts = append(opts, http.Address(h.conf.Addr))
+	if c.Addr != "" {
+		opts = append(opts, http.Address(c.Addr))
 	}
 	// 如果配置中指定了超时时间，则将其添加到 HTTP 服务器选项中。
-	if h.conf.Timeout != nil {
-		opts = append(opts, http.Timeout(h.conf.Timeout.AsDuration()))
+	if c.Timeout != nil {
+		opts = append(opts, http.Timeout(c.Timeout.AsDuration()))
 	}
 	// 如果配置中启用了 TLS，则加载 TLS 配置并将其添加到 HTTP 服务器选项中。
-	if h.conf.GetTls() {
-		opts = append(opts, h.tlsLoad())
+	if c.GetTls() {
+		opts = append(opts, h.tlsLoad(c))
 	}
 
+	// 通过 Lynx 获取监听器，优雅重启时沿用上一个进程的监听器
+	lis, err := app.Listen(listener, c.Network, c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, http.Listener(lis))
+
 	// 创建一个新的 HTTP 服务器实例，使用之前定义的选项进行配置。
-	h.http = http.NewServer(opts...)
-	// 使用 Lynx 应用的 Helper 记录 HTTP 服务初始化成功的信息。
-	app.Lynx().Helper().Infof("HTTP service successfully initialized")
-	// 返回 HTTP 服务实例和 nil 错误，表示加载成功。
-	return h, nil
+	return http.NewServer(opts...), nil
 }
 
 // Unload 方法用于停止并关闭 HTTP 服务器。
@@ -107,12 +134,19 @@ func (h *ServiceHttp) Unload() error {
 	// 调用 HTTP 服务器的 Close 方法来停止服务器，并传入一个 nil 参数。
 	// 如果 Close 方法返回错误，则记录错误信息。
 	if err := h.http.Close(); err != nil {
-		// 使用 app.Lynx().Helper() 记录错误信息。
-		app.Lynx().Helper().Error(err)
+		// 使用 app.Lynx().PluginHelper(name) 记录错误信息。
+		app.Lynx().PluginHelper(name).Error(err)
 		return err
 	}
+	// 关闭所有具名服务器
+	for n, s := range h.named {
+		if err := s.Close(); err != nil {
+			app.Lynx().PluginHelper(name).Errorf("failed to close http server %v: %v", n, err)
+			return err
+		}
+	}
 	// 记录一条信息，指示 HTTP 资源正在被关闭。
-	app.Lynx().Helper().Info("message", "Closing the HTTP resources")
+	app.Lynx().PluginHelper(name).Info("message", "Closing the HTTP resources")
 	// 返回 nil，表示卸载过程成功，没有发生错误。
 	return nil
 }
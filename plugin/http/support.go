@@ -1,9 +1,14 @@
 package http
 
 import (
+	"context"
 	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/plugin"
 	"github.com/go-lynx/lynx/plugin/cert"
 	"github.com/go-lynx/lynx/plugin/http/conf"
+	"sort"
 )
 
 func (h *ServiceHttp) Name() string {
@@ -23,6 +28,11 @@ func (h *ServiceHttp) DependsOn(b config.Value) []string {
 	if c.Tls {
 		return []string{cert.GetName()}
 	}
+	for _, s := range c.GetServers() {
+		if s.GetTls() {
+			return []string{cert.GetName()}
+		}
+	}
 	return nil
 }
 
@@ -33,3 +43,38 @@ func (h *ServiceHttp) ConfPrefix() string {
 func (h *ServiceHttp) Weight() int {
 	return h.weight
 }
+
+func (h *ServiceHttp) ShutdownPhase() string {
+	return plugin.PhaseIngress
+}
+
+func (h *ServiceHttp) Servers() []transport.Server {
+	servers := []transport.Server{h.http}
+	names := make([]string, 0, len(h.named))
+	for n := range h.named {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		servers = append(servers, h.named[n])
+	}
+	return servers
+}
+
+// Drain stops accepting connections and waits for the active requests of all servers to finish
+func (h *ServiceHttp) Drain(ctx context.Context) error {
+	if h.http == nil {
+		return nil
+	}
+	servers := []*http.Server{h.http}
+	for _, s := range h.named {
+		servers = append(servers, s)
+	}
+	var err error
+	for _, s := range servers {
+		if shutdownErr := s.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
@@ -2,25 +2,16 @@ package http
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx/app"
+	"github.com/go-lynx/lynx/plugin/http/conf"
 )
 
-func (h *ServiceHttp) tlsLoad() http.ServerOption {
-	tlsCert, err := tls.X509KeyPair(app.Lynx().Cert().GetCrt(), app.Lynx().Cert().GetKey())
+func (h *ServiceHttp) tlsLoad(c *conf.Http) http.ServerOption {
+	// 启动时校验证书，之后每次握手都读取当前证书与根证书，证书轮换无需重启
+	tlsConf, err := app.ServerTLSConfig(tls.ClientAuthType(c.GetTlsAuthType()), "h2", "http/1.1")
 	if err != nil {
 		panic(err)
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(app.Lynx().Cert().GetRootCA()) {
-		panic(err)
-	}
-
-	return http.TLSConfig(&tls.Config{
-		Certificates: []tls.Certificate{tlsCert},
-		ClientCAs:    certPool,
-		ServerName:   app.Name(),
-		ClientAuth:   tls.ClientAuthType(h.conf.GetTlsAuthType()),
-	})
+	return http.TLSConfig(tlsConf)
 }
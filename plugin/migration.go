@@ -0,0 +1,16 @@
+package plugin
+
+// ConfigMigration upgrades a plugin configuration written for an older version of the plugin
+type ConfigMigration struct {
+	// Description explains the schema change, it is logged as a deprecation warning when the migration applies
+	Description string
+	// Migrate rewrites the configuration in place and reports whether it changed anything
+	Migrate func(c map[string]interface{}) (bool, error)
+}
+
+// ConfigMigrator is implemented by plugins whose configuration schema changed between versions. The migrations
+// run in order on the configuration under ConfPrefix before Load, so configurations written for older versions
+// keep working.
+type ConfigMigrator interface {
+	ConfigMigrations() []ConfigMigration
+}
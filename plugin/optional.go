@@ -0,0 +1,10 @@
+package plugin
+
+import "github.com/go-kratos/kratos/v2/config"
+
+// OptionalDependent is implemented by plugins that use other plugins when they are present but work without them,
+// e.g. a plugin that traces its calls when the tracer plugin is configured. A present optional dependency is
+// loaded first, an absent one is ignored.
+type OptionalDependent interface {
+	OptionalDependsOn(config.Value) []string
+}
@@ -0,0 +1,21 @@
+package plugin
+
+// Shutdown phases, plugins are unloaded phase by phase in the order listed below
+const (
+	// PhaseIngress holds plugins that accept traffic, they stop first so no new work comes in
+	PhaseIngress = "ingress"
+	// PhaseWorkers holds background plugins, they finish their work once ingress has stopped
+	PhaseWorkers = "workers"
+	// PhaseStores holds plugins that own storage connections, they close last
+	PhaseStores = "stores"
+)
+
+// ShutdownPhases lists the shutdown phases in the order they are processed
+var ShutdownPhases = []string{PhaseIngress, PhaseWorkers, PhaseStores}
+
+// ShutdownPhaser is implemented by plugins that declare the shutdown phase they belong to,
+// plugins that don't implement it are unloaded in the workers phase
+type ShutdownPhaser interface {
+	// ShutdownPhase returns one of PhaseIngress, PhaseWorkers or PhaseStores
+	ShutdownPhase() string
+}
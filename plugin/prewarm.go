@@ -0,0 +1,10 @@
+package plugin
+
+import "context"
+
+// PreWarmer is implemented by pool-backed plugins that can establish connections ahead of the first requests.
+// PreWarm is called after Load when lynx.plugins.prewarm configures a connection count for the plugin, the plugin
+// is not ready until it returns.
+type PreWarmer interface {
+	PreWarm(ctx context.Context, n int) error
+}
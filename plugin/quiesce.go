@@ -0,0 +1,11 @@
+package plugin
+
+import "context"
+
+// Quiescer is implemented by plugins running background work such as consumers or schedulers. Quiesce pauses
+// taking new work and waits for in-flight work to finish and flush without releasing resources, Resume picks
+// the work up again.
+type Quiescer interface {
+	Quiesce(ctx context.Context) error
+	Resume(ctx context.Context) error
+}
@@ -0,0 +1,24 @@
+package plugin
+
+import "context"
+
+// ReadinessDependency is a named check of an external service a plugin needs in order to serve
+type ReadinessDependency struct {
+	// Name identifies the dependency in readiness reports, e.g. "redis:127.0.0.1:6379"
+	Name string
+	// Check returns nil when the dependency is reachable
+	Check func(ctx context.Context) error
+}
+
+// ReadinessProvider is implemented by plugins whose readiness depends on external services, a loaded plugin
+// is only ready when all of its readiness dependencies pass
+type ReadinessProvider interface {
+	ReadinessDependencies() []ReadinessDependency
+}
+
+// Ready is implemented by plugins that keep getting ready after Load returns, e.g. until their broker is
+// reachable. Plugins depending on them are only loaded once WaitReady returns nil, plugins that don't implement
+// it are ready when Load returns.
+type Ready interface {
+	WaitReady(ctx context.Context) error
+}
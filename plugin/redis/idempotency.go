@@ -0,0 +1,231 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx/app"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"time"
+)
+
+// ErrRequestInProgress is returned while another instance handles a request with the same idempotency key
+var ErrRequestInProgress = errors.Conflict("REQUEST_IN_PROGRESS", "a request with this idempotency key is in progress")
+
+// ErrIdempotencyKeyReused is returned when a key is sent again with a different request than the one it was first used for
+var ErrIdempotencyKeyReused = errors.New(422, "IDEMPOTENCY_KEY_REUSED", "the idempotency key was used for a different request")
+
+// releaseLock deletes a lock only while it still holds the token of the caller, a lock that expired and was taken
+// by another request is left alone
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type idempotency struct {
+	header  string
+	ttl     time.Duration
+	lockTTL time.Duration
+	flight  singleflight.Group
+	// rdb returns the client keys are stored in, the one of the redis plugin unless replaced in tests
+	rdb func() *redis.Client
+}
+
+type IdempotencyOption func(i *idempotency)
+
+// IdempotencyHeader sets the request header carrying the key, defaults to Idempotency-Key
+func IdempotencyHeader(h string) IdempotencyOption {
+	return func(i *idempotency) {
+		i.header = h
+	}
+}
+
+// IdempotencyTTL sets how long responses are replayed for retries, defaults to 24h
+func IdempotencyTTL(d time.Duration) IdempotencyOption {
+	return func(i *idempotency) {
+		i.ttl = d
+	}
+}
+
+// IdempotencyLockTTL bounds how long a request holds its key while in flight, defaults to 30s
+func IdempotencyLockTTL(d time.Duration) IdempotencyOption {
+	return func(i *idempotency) {
+		i.lockTTL = d
+	}
+}
+
+// Idempotency returns a server middleware that replays the first successful response of a request for every
+// retry carrying the same idempotency key, e.g. http.GetServer().Use("/user.v1.User/CreateUser", redis.Idempotency()).
+// Duplicates arriving while the first request is in flight share its result on the same instance and fail with
+// ErrRequestInProgress on other instances. A key sent again with a different request fails with
+// ErrIdempotencyKeyReused. Errors are not stored so failed requests can be retried, and only protobuf responses
+// are stored.
+func Idempotency(opts ...IdempotencyOption) middleware.Middleware {
+	i := &idempotency{
+		header:  "Idempotency-Key",
+		ttl:     24 * time.Hour,
+		lockTTL: 30 * time.Second,
+		rdb:     GetRedis,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || tr.RequestHeader().Get(i.header) == "" {
+				return handler(ctx, req)
+			}
+			key := "lynx:idempotency:" + tr.Operation() + ":" + tr.RequestHeader().Get(i.header)
+			fp, err := fingerprint(req)
+			if err != nil {
+				return nil, err
+			}
+
+			if reply, err := i.load(ctx, key, fp); err != nil || reply != nil {
+				return reply, err
+			}
+			// The shared call outlives the caller that started it, so one duplicate giving up doesn't fail
+			// the others. It is bounded by the lock TTL instead. Only identical requests share a call, a
+			// different one with the same key finds the key locked.
+			result := i.flight.DoChan(key+":"+fp, func() (interface{}, error) {
+				ctx, cancel := context.WithTimeout(detach(ctx), i.lockTTL)
+				defer cancel()
+				return i.handle(ctx, key, fp, req, handler)
+			})
+			select {
+			case r := <-result:
+				return r.Val, r.Err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// load returns the stored response of key, or nil when there is none. It fails with ErrIdempotencyKeyReused when
+// the response was stored for a request with another fingerprint.
+func (i *idempotency) load(ctx context.Context, key string, fp string) (interface{}, error) {
+	data, err := i.rdb().Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The value is the fingerprint of the request followed by the response
+	if len(data) < len(fp) || !bytes.Equal(data[:len(fp)], []byte(fp)) {
+		return nil, ErrIdempotencyKeyReused
+	}
+	stored := &anypb.Any{}
+	if err := proto.Unmarshal(data[len(fp):], stored); err != nil {
+		return nil, err
+	}
+	return stored.UnmarshalNew()
+}
+
+// handle runs the handler while holding the key and stores a successful response
+func (i *idempotency) handle(ctx context.Context, key, fp string, req interface{}, handler middleware.Handler) (interface{}, error) {
+	lock := key + ":lock"
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+	acquired, err := i.rdb().SetNX(ctx, lock, token, i.lockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrRequestInProgress
+	}
+	defer func() {
+		if err := releaseLock.Run(context.Background(), i.rdb(), []string{lock}, token).Err(); err != nil {
+			app.Lynx().PluginHelper(name).Warnf("failed to release idempotency lock %v: %v", lock, err)
+		}
+	}()
+
+	// The first request may have finished between the lookup and taking the lock
+	if reply, err := i.load(ctx, key, fp); err != nil || reply != nil {
+		return reply, err
+	}
+	reply, err := handler(ctx, req)
+	if err != nil {
+		return reply, err
+	}
+	if msg, ok := reply.(proto.Message); ok {
+		if err := i.store(ctx, key, fp, msg); err != nil {
+			app.Lynx().PluginHelper(name).Warnf("failed to store idempotent response of %v: %v", key, err)
+		}
+	}
+	return reply, nil
+}
+
+func (i *idempotency) store(ctx context.Context, key, fp string, msg proto.Message) error {
+	stored, err := anypb.New(msg)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return i.rdb().Set(ctx, key, append([]byte(fp), data...), i.ttl).Err()
+}
+
+// fingerprint returns a hex digest identifying the content of a request, protobuf requests are hashed in their
+// deterministic wire form and others in JSON
+func fingerprint(req interface{}) (string, error) {
+	var data []byte
+	var err error
+	if msg, ok := req.(proto.Message); ok {
+		data, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	} else {
+		data, err = json.Marshal(req)
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lockToken returns a random token identifying the holder of a lock
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// detachedContext keeps the values of its parent but not its deadline and cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,366 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx/app"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves the commands the idempotency middleware uses over the Redis protocol, keeping the keys in memory
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+// newFakeRedis starts a fake server and returns a client connected to it, with a Lynx application for the warnings
+// of the middleware
+func newFakeRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+	data := []byte(`{"lynx": {"application": {"name": "test", "version": "v1", "close_banner": true}}}`)
+	c := config.New(config.WithSource(app.NewStaticSource("test", data, "json")))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a := app.NewApp(c)
+	a.SetLogger(log.NewStdLogger(io.Discard))
+	a.InitLogger()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{values: map[string]string{}, expires: map[string]time.Time{}}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	rdb := redis.NewClient(&redis.Options{Addr: l.Addr().String()})
+	t.Cleanup(func() {
+		rdb.Close()
+		l.Close()
+		c.Close()
+	})
+	return f, rdb
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "CLIENT":
+		return "+OK\r\n"
+	case "GET":
+		v, ok := f.get(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		// SET key value [EX seconds | PX milliseconds] [NX]
+		var ttl time.Duration
+		nx := false
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "EX", "PX":
+				n, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(n) * time.Millisecond
+				if strings.ToUpper(args[i]) == "EX" {
+					ttl = time.Duration(n) * time.Second
+				}
+				i++
+			case "NX":
+				nx = true
+			}
+		}
+		if _, ok := f.get(args[1]); ok && nx {
+			return "$-1\r\n"
+		}
+		f.values[args[1]] = args[2]
+		delete(f.expires, args[1])
+		if ttl > 0 {
+			f.expires[args[1]] = time.Now().Add(ttl)
+		}
+		return "+OK\r\n"
+	case "EVALSHA":
+		return "-NOSCRIPT No matching script\r\n"
+	case "EVAL":
+		// The only script is releaseLock: EVAL script 1 key token
+		if v, ok := f.get(args[3]); ok && v == args[4] {
+			delete(f.values, args[3])
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// get returns the value of a key that hasn't expired
+func (f *fakeRedis) get(key string) (string, bool) {
+	if exp, ok := f.expires[key]; ok && time.Now().After(exp) {
+		delete(f.values, key)
+		delete(f.expires, key)
+	}
+	v, ok := f.values[key]
+	return v, ok
+}
+
+func (f *fakeRedis) value(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.get(key)
+}
+
+type header map[string]string
+
+func (h header) Get(key string) string {
+	return h[key]
+}
+
+func (h header) Set(key, value string) {
+	h[key] = value
+}
+
+func (h header) Add(key, value string) {
+	h[key] = value
+}
+
+func (h header) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (h header) Values(key string) []string {
+	return []string{h[key]}
+}
+
+type testTransport struct {
+	key string
+}
+
+func (t *testTransport) Kind() transport.Kind {
+	return transport.KindHTTP
+}
+
+func (t *testTransport) Endpoint() string {
+	return ""
+}
+
+func (t *testTransport) Operation() string {
+	return "/test.v1.Test/Create"
+}
+
+func (t *testTransport) RequestHeader() transport.Header {
+	return header{"Idempotency-Key": t.key}
+}
+
+func (t *testTransport) ReplyHeader() transport.Header {
+	return header{}
+}
+
+func withClient(rdb *redis.Client) IdempotencyOption {
+	return func(i *idempotency) {
+		i.rdb = func() *redis.Client {
+			return rdb
+		}
+	}
+}
+
+// countingHandler replies with the request and counts its calls, it blocks while release is open
+type countingHandler struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (h *countingHandler) handle(_ context.Context, req interface{}) (interface{}, error) {
+	h.calls.Add(1)
+	if h.release != nil {
+		<-h.release
+	}
+	return wrapperspb.String("created " + req.(*wrapperspb.StringValue).GetValue()), nil
+}
+
+func call(i func(context.Context, interface{}) (interface{}, error), key, body string) (string, error) {
+	ctx := transport.NewServerContext(context.Background(), &testTransport{key: key})
+	reply, err := i(ctx, wrapperspb.String(body))
+	if err != nil {
+		return "", err
+	}
+	return reply.(*wrapperspb.StringValue).GetValue(), nil
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	_, rdb := newFakeRedis(t)
+	h := &countingHandler{}
+	handler := Idempotency(withClient(rdb))(h.handle)
+
+	first, err := call(handler, "k1", "order")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	replay, err := call(handler, "k1", "order")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != "created order" || replay != first {
+		t.Errorf("Expected the first response to be replayed, but got %q and %q", first, replay)
+	}
+	if h.calls.Load() != 1 {
+		t.Errorf("Expected the handler to run once, but it ran %v times", h.calls.Load())
+	}
+
+	if _, err := call(handler, "k1", "other order"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected %v for a different request with the same key, but got %v", ErrIdempotencyKeyReused, err)
+	}
+	if _, err := call(handler, "k2", "other order"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if h.calls.Load() != 2 {
+		t.Errorf("Expected the handler to run for the new key, but it ran %v times", h.calls.Load())
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	_, rdb := newFakeRedis(t)
+	h := &countingHandler{release: make(chan struct{})}
+	// Two middlewares stand for two instances sharing the Redis
+	first := Idempotency(withClient(rdb))(h.handle)
+	second := Idempotency(withClient(rdb))(h.handle)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := call(first, "k1", "order")
+		done <- err
+	}()
+	for h.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := call(second, "k1", "order"); !errors.Is(err, ErrRequestInProgress) {
+		t.Errorf("Expected %v while the first request is in flight, but got %v", ErrRequestInProgress, err)
+	}
+	close(h.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reply, err := call(second, "k1", "order"); err != nil || reply != "created order" {
+		t.Errorf("Expected the stored response once the first request finished, but got %q, %v", reply, err)
+	}
+}
+
+func TestIdempotencyLockExpiry(t *testing.T) {
+	f, rdb := newFakeRedis(t)
+	slow := &countingHandler{release: make(chan struct{})}
+	next := &countingHandler{release: make(chan struct{})}
+	first := Idempotency(withClient(rdb), IdempotencyLockTTL(50*time.Millisecond))(slow.handle)
+	second := Idempotency(withClient(rdb), IdempotencyLockTTL(time.Minute))(next.handle)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := call(first, "k1", "order")
+		done <- err
+	}()
+	for slow.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// The lock of the slow request expires and a retry takes the key over
+	time.Sleep(60 * time.Millisecond)
+	retried := make(chan error, 1)
+	go func() {
+		_, err := call(second, "k1", "order")
+		retried <- err
+	}()
+	for next.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	lock := "lynx:idempotency:/test.v1.Test/Create:k1:lock"
+	token, _ := f.value(lock)
+
+	close(slow.release)
+	<-done
+	if v, ok := f.value(lock); !ok || v != token {
+		t.Error("Expected the slow request to leave the lock of the retry alone")
+	}
+	close(next.release)
+	if err := <-retried; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := f.value(lock); ok {
+		t.Error("Expected the retry to release its lock")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a, _ := fingerprint(wrapperspb.String("order"))
+	b, _ := fingerprint(proto.Clone(wrapperspb.String("order")))
+	c, _ := fingerprint(wrapperspb.String("other order"))
+	if a != b || a == c {
+		t.Errorf("Expected equal requests to share a fingerprint and different ones not to")
+	}
+}
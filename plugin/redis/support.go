@@ -1,6 +1,11 @@
 package redis
 
-import "github.com/go-kratos/kratos/v2/config"
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx/plugin"
+	"github.com/redis/go-redis/v9"
+)
 
 func (r *PlugRedis) Name() string {
 	return name
@@ -17,3 +22,43 @@ func (r *PlugRedis) ConfPrefix() string {
 func (r *PlugRedis) Weight() int {
 	return r.weight
 }
+
+func (r *PlugRedis) ShutdownPhase() string {
+	return plugin.PhaseStores
+}
+
+func (r *PlugRedis) ReadinessDependencies() []plugin.ReadinessDependency {
+	return []plugin.ReadinessDependency{
+		{
+			Name: "redis:" + r.conf.GetAddr(),
+			Check: func(ctx context.Context) error {
+				return r.rdb.Ping(ctx).Err()
+			},
+		},
+	}
+}
+
+// PreWarm dials n connections and returns them to the pool, at most the pool size
+func (r *PlugRedis) PreWarm(ctx context.Context, n int) error {
+	if size := r.rdb.Options().PoolSize; size > 0 && n > size {
+		n = size
+	}
+	conns := make([]*redis.Conn, 0, n)
+	defer func() {
+		for _, c := range conns {
+			_ = c.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		c := r.rdb.Conn()
+		conns = append(conns, c)
+		if err := c.Ping(ctx).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PlugRedis) CheckHealth(ctx context.Context) error {
+	return r.rdb.Ping(ctx).Err()
+}
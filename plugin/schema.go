@@ -0,0 +1,8 @@
+package plugin
+
+// SchemaProvider is implemented by plugins that describe their configuration with a JSON schema, validation
+// checks the configuration under ConfPrefix against it before deploying. The keywords type, properties,
+// required, additionalProperties, items and enum are supported.
+type SchemaProvider interface {
+	ConfigSchema() []byte
+}
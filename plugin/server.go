@@ -0,0 +1,9 @@
+package plugin
+
+import "github.com/go-kratos/kratos/v2/transport"
+
+// ServerProvider is implemented by plugins that serve traffic, the servers are handed to the Kratos application
+// when it is assembled by the boot builder
+type ServerProvider interface {
+	Servers() []transport.Server
+}
@@ -27,6 +27,11 @@ type Tracer struct {
 
 	Addr  string  `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
 	Ratio float32 `protobuf:"fixed32,2,opt,name=ratio,proto3" json:"ratio,omitempty"`
+	// Where spans are exported to: otlp (default) sends them to addr, stdout and file print readable span trees
+	// for local development
+	Exporter string `protobuf:"bytes,3,opt,name=exporter,proto3" json:"exporter,omitempty"`
+	// The file span trees are appended to when exporter is file
+	File string `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
 }
 
 func (x *Tracer) Reset() {
@@ -75,15 +80,32 @@ func (x *Tracer) GetRatio() float32 {
 	return 0
 }
 
+func (x *Tracer) GetExporter() string {
+	if x != nil {
+		return x.Exporter
+	}
+	return ""
+}
+
+func (x *Tracer) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
 var File_tracer_proto protoreflect.FileDescriptor
 
 var file_tracer_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1b,
 	0x6c, 0x79, 0x6e, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x70, 0x6c,
-	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x22, 0x32, 0x0a, 0x06, 0x54,
+	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x22, 0x62, 0x0a, 0x06, 0x54,
 	0x72, 0x61, 0x63, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x42,
+	0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x42,
 	0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f,
 	0x2d, 0x6c, 0x79, 0x6e, 0x78, 0x2f, 0x6c, 0x79, 0x6e, 0x78, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69,
 	0x6e, 0x2f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x62, 0x06, 0x70,
@@ -2,6 +2,7 @@ package tracer
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-lynx/lynx/app"
 	"github.com/go-lynx/lynx/plugin"
@@ -21,6 +22,7 @@ var (
 type PlugTracer struct {
 	conf   *conf.Tracer
 	weight int
+	tp     *traceSdk.TracerProvider
 }
 
 type Option func(t *PlugTracer)
@@ -48,16 +50,8 @@ func (t *PlugTracer) Load(b config.Value) (plugin.Plugin, error) {
 	// 使用 Lynx 应用的 Helper 记录日志，指示正在初始化链路监控组件
 	app.Lynx().Helper().Infof("Initializing link monitoring component")
 
-	// 创建一个新的 ot-lp 跟踪导出器，用于将跟踪数据发送到指定的端点
-	exp, err := otlptracegrpc.New(
-		context.Background(),
-		// 设置导出器的端点地址
-		otlptracegrpc.WithEndpoint(t.conf.GetAddr()),
-		// 禁用 TLS 加密，使用不安全的连接
-		otlptracegrpc.WithInsecure(),
-		// 使用 gzip 压缩算法来压缩跟踪数据
-		otlptracegrpc.WithCompressor("gzip"),
-	)
+	// 根据配置创建导出器，默认将跟踪数据发送到 ot-lp 收集器
+	exp, err := t.exporter()
 	// 如果创建导出器时发生错误，返回 nil 和错误信息
 	if err != nil {
 		return nil, err
@@ -85,6 +79,7 @@ func (t *PlugTracer) Load(b config.Value) (plugin.Plugin, error) {
 
 	// 设置全局跟踪提供者，用于后续的跟踪数据生成和处理
 	otel.SetTracerProvider(tp)
+	t.tp = tp
 
 	// 使用 Lynx 应用的 Helper 记录日志，指示链路监控组件初始化成功
 	app.Lynx().Helper().Infof("Link monitoring component successfully initialized")
@@ -94,7 +89,36 @@ func (t *PlugTracer) Load(b config.Value) (plugin.Plugin, error) {
 }
 
 func (t *PlugTracer) Unload() error {
-	return nil
+	if t.tp == nil {
+		return nil
+	}
+	// Flush the remaining spans and close the exporter
+	return t.tp.Shutdown(context.Background())
+}
+
+// exporter creates the span exporter selected by the exporter option
+func (t *PlugTracer) exporter() (traceSdk.SpanExporter, error) {
+	switch t.conf.GetExporter() {
+	case "", ExporterOTLP:
+		return otlptracegrpc.New(
+			context.Background(),
+			// 设置导出器的端点地址
+			otlptracegrpc.WithEndpoint(t.conf.GetAddr()),
+			// 禁用 TLS 加密，使用不安全的连接
+			otlptracegrpc.WithInsecure(),
+			// 使用 gzip 压缩算法来压缩跟踪数据
+			otlptracegrpc.WithCompressor("gzip"),
+		)
+	case ExporterStdout:
+		return newTreeExporter("")
+	case ExporterFile:
+		if t.conf.GetFile() == "" {
+			return nil, fmt.Errorf("tracer exporter %v requires a file", ExporterFile)
+		}
+		return newTreeExporter(t.conf.GetFile())
+	default:
+		return nil, fmt.Errorf("unknown tracer exporter %v", t.conf.GetExporter())
+	}
 }
 
 func Tracer(opts ...Option) plugin.Plugin {
@@ -0,0 +1,108 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	traceSdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exporters selectable with lynx.tracer.exporter
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// treeExporter prints the spans of every exported batch as indented trees, one per trace. It is meant for local
+// development without a collector, spans whose parent is not part of the batch start a tree of their own.
+type treeExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// newTreeExporter returns an exporter writing to stdout, or appending to file when it is not empty
+func newTreeExporter(file string) (*treeExporter, error) {
+	if file == "" {
+		return &treeExporter{w: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &treeExporter{w: f, closer: f}, nil
+}
+
+func (e *treeExporter) ExportSpans(_ context.Context, spans []traceSdk.ReadOnlySpan) error {
+	var traces []trace.TraceID
+	roots := make(map[trace.TraceID][]traceSdk.ReadOnlySpan)
+	children := make(map[trace.SpanID][]traceSdk.ReadOnlySpan)
+	inBatch := make(map[trace.SpanID]bool, len(spans))
+	for _, s := range spans {
+		inBatch[s.SpanContext().SpanID()] = true
+	}
+	for _, s := range spans {
+		if s.Parent().IsValid() && inBatch[s.Parent().SpanID()] {
+			children[s.Parent().SpanID()] = append(children[s.Parent().SpanID()], s)
+			continue
+		}
+		id := s.SpanContext().TraceID()
+		if _, ok := roots[id]; !ok {
+			traces = append(traces, id)
+		}
+		roots[id] = append(roots[id], s)
+	}
+
+	var b strings.Builder
+	for _, id := range traces {
+		fmt.Fprintf(&b, "trace %v\n", id)
+		for _, s := range byStart(roots[id]) {
+			writeSpan(&b, s, children, 1)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *treeExporter) Shutdown(context.Context) error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// writeSpan renders a span and its children, e.g. "  GET /users 12.3ms http.method=GET"
+func writeSpan(b *strings.Builder, s traceSdk.ReadOnlySpan, children map[trace.SpanID][]traceSdk.ReadOnlySpan, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(s.Name())
+	b.WriteString(" ")
+	b.WriteString(s.EndTime().Sub(s.StartTime()).Round(time.Microsecond).String())
+	for _, attr := range s.Attributes() {
+		fmt.Fprintf(b, " %v=%v", attr.Key, attr.Value.Emit())
+	}
+	if s.Status().Code == codes.Error {
+		fmt.Fprintf(b, " error=%q", s.Status().Description)
+	}
+	b.WriteString("\n")
+	for _, c := range byStart(children[s.SpanContext().SpanID()]) {
+		writeSpan(b, c, children, depth+1)
+	}
+}
+
+func byStart(spans []traceSdk.ReadOnlySpan) []traceSdk.ReadOnlySpan {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].StartTime().Before(spans[j].StartTime())
+	})
+	return spans
+}